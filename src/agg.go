@@ -0,0 +1,94 @@
+package goqdsl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// JSONAgg renders json_agg(expr), ordered inside the aggregate by order, for
+// the select list:
+//
+//  NewQ().Select("p.uuid").SelectExpr(As(JSONAgg(JSONBBuildObject("uuid", "c.uuid", "name", "c.name"), Asc("c.name")), "children")).
+//    FromTable(parents).InnerJoin([]Join{children.On("p.uuid", "c.parent_uuid")}).GroupBy("p.uuid")
+//
+// Like the other helpers here, it takes a column or another Expr, and the
+// result passes CheckIdentifiers when its columns do.
+func JSONAgg[E string | Expr](expr E, order ...Order) Expr {
+  return aggregate("json_agg", expr, order)
+}
+
+// ArrayAgg renders array_agg(expr), ordered inside the aggregate by order.
+func ArrayAgg[E string | Expr](expr E, order ...Order) Expr {
+  return aggregate("array_agg", expr, order)
+}
+
+// JSONBBuildObject renders jsonb_build_object over key, value pairs. Keys are
+// string literals, values columns. A key without a value is left out and
+// fails CheckIdentifiers.
+func JSONBBuildObject(pairs ...string) Expr {
+  var err error
+  if len(pairs)%2 != 0 {
+    err = fmt.Errorf("goqdsl: JSONBBuildObject key %s has no value", quote(pairs[len(pairs)-1]))
+    pairs = pairs[:len(pairs)-1]
+  }
+  var sb strings.Builder
+  values := make([]string, 0, len(pairs)/2)
+  sb.WriteString("jsonb_build_object(")
+  for i := 0; i < len(pairs); i += 2 {
+    if i > 0 {
+      sb.WriteString(", ")
+    }
+    sb.WriteString(quote(pairs[i]))
+    sb.WriteString(", ")
+    sb.WriteString(pairs[i+1])
+    values = append(values, pairs[i+1])
+  }
+  sb.WriteByte(')')
+  return trusted(sb.String(), err, values...)
+}
+
+// As renders expr AS alias.
+func As[E string | Expr](expr E, alias string) Expr {
+  sql, err := exprPart(expr)
+  return trusted(sql+" AS "+alias, err, alias)
+}
+
+func aggregate[E string | Expr](fn string, expr E, order []Order) Expr {
+  sql, err := exprPart(expr)
+  var sb strings.Builder
+  columns := make([]string, 0, len(order))
+  sb.WriteString(fn)
+  sb.WriteByte('(')
+  sb.WriteString(sql)
+  for i, o := range order {
+    if i == 0 {
+      sb.WriteString(" ORDER BY ")
+    } else {
+      sb.WriteString(", ")
+    }
+    sb.WriteString(o.String())
+    columns = append(columns, o.column)
+  }
+  sb.WriteByte(')')
+  return trusted(sb.String(), err, columns...)
+}
+
+// exprPart returns the SQL of e, checked as a select item when it is a string.
+func exprPart[E string | Expr](e E) (string, error) {
+  if x, ok := any(e).(Expr); ok {
+    return x.sql, x.err
+  }
+  s, _ := any(e).(string)
+  return s, checkIdent("expression", selectItem, s)
+}
+
+// trusted returns sql as an Expr that passes CheckIdentifiers if err is nil
+// and all of parts pass on their own.
+func trusted(sql string, err error, parts ...string) Expr {
+  if err == nil {
+    err = checkIdent("expression", selectItem, parts...)
+  }
+  return Expr{sql: sql, err: err}
+}
+
+// end
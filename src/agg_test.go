@@ -0,0 +1,46 @@
+package goqdsl
+
+import (
+	"testing"
+)
+
+func TestJSONAgg(t *testing.T) {
+  parents, children := T("parents").As("p"), T("children").As("c")
+  q := NewQ().Select("p.uuid").SelectExpr(As(JSONAgg(JSONBBuildObject("uuid", "c.uuid", "name", "c.name"), Asc("c.name")), "children")).
+    FromTable(parents).InnerJoin([]Join{children.On("p.uuid", "c.parent_uuid")}).GroupBy("p.uuid")
+
+  expected := "SELECT p.uuid, json_agg(jsonb_build_object('uuid', c.uuid, 'name', c.name) ORDER BY c.name ASC) AS children " +
+    "FROM parents p INNER JOIN children c ON p.uuid = c.parent_uuid GROUP BY p.uuid "
+  if sql := q.Query(); sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+  if err := CheckIdentifiers(q); err != nil {
+    t.Error(err)
+  }
+}
+
+func TestArrayAgg(t *testing.T) {
+  if s := ArrayAgg("c.name", Desc("c.created"), Asc("c.name")).String(); s != "array_agg(c.name ORDER BY c.created DESC, c.name ASC)" {
+    t.Errorf("unexpected aggregate: %s", s)
+  }
+  if s := JSONBBuildObject("it's", "x").String(); s != "jsonb_build_object('it''s', x)" {
+    t.Errorf("expected a quoted key, got %s", s)
+  }
+
+  odd := JSONBBuildObject("uuid", "c.uuid", "name")
+  if s := odd.String(); s != "jsonb_build_object('uuid', c.uuid)" {
+    t.Errorf("expected the dangling key left out, got %s", s)
+  }
+  if err := CheckIdentifiers(NewQ().SelectExpr(odd).From("c")); err == nil {
+    t.Error("expected an error for a key without a value")
+  }
+}
+
+func TestAggUntrusted(t *testing.T) {
+  q := NewQ().SelectExpr(ArrayAgg("name); DROP TABLE foo; --")).From("foo")
+  if err := CheckIdentifiers(q); err == nil {
+    t.Error("expected an identifier error")
+  }
+}
+
+// end
@@ -0,0 +1,131 @@
+package goqdsl
+
+import (
+	"testing"
+)
+
+func benchQ() *Q {
+  return NewQ().Select("uuid", "name", "created", "updated").From("foo").
+    Where(map[string]string{"name": "bar", "foo.uuid": "d3b2aa81", "active": "true"})
+}
+
+func BenchmarkQuery(b *testing.B) {
+  q := benchQ()
+  b.ReportAllocs()
+  for i := 0; i < b.N; i++ {
+    q.Query()
+  }
+}
+
+func BenchmarkBuildNamed(b *testing.B) {
+  q := benchQ()
+  b.ReportAllocs()
+  for i := 0; i < b.N; i++ {
+    q.BuildNamed()
+  }
+}
+
+func BenchmarkBuildPositional(b *testing.B) {
+  q := benchQ()
+  b.ReportAllocs()
+  for i := 0; i < b.N; i++ {
+    q.BuildPositional()
+  }
+}
+
+// The benchmarks above hit the build cache after the first round; these
+// render every time.
+func BenchmarkQueryUncached(b *testing.B) {
+  q := benchQ()
+  b.ReportAllocs()
+  for i := 0; i < b.N; i++ {
+    q.build(nil)
+  }
+}
+
+func BenchmarkBuildNamedUncached(b *testing.B) {
+  q := benchQ()
+  b.ReportAllocs()
+  for i := 0; i < b.N; i++ {
+    q.buildNamed()
+  }
+}
+
+func benchFilteredQ() *Q {
+  return NewQ().Select("uuid", "name").From("foo").
+    Where(map[string]string{"name": "bar"}).
+    Filter(Column[int]("age").In(30, 40, 50), Column[string]("name").Eq("baz"), Or(Column[string]("kind").Eq("a"), Column[string]("kind").Eq("b"))).
+    IsNull("deleted").GroupBy("uuid", "name").OrderBy(Desc("created"), Asc("name"))
+}
+
+func BenchmarkFilteredQueryUncached(b *testing.B) {
+  q := benchFilteredQ()
+  b.ReportAllocs()
+  for i := 0; i < b.N; i++ {
+    q.build(nil)
+  }
+}
+
+func BenchmarkFilteredBuildNamedUncached(b *testing.B) {
+  q := benchFilteredQ()
+  b.ReportAllocs()
+  for i := 0; i < b.N; i++ {
+    q.buildNamed()
+  }
+}
+
+func BenchmarkFilteredBuildPositionalUncached(b *testing.B) {
+  q := benchFilteredQ()
+  b.ReportAllocs()
+  for i := 0; i < b.N; i++ {
+    q.buildPositional()
+  }
+}
+
+func BenchmarkUpsertBuildNamed(b *testing.B) {
+  u := Upsert("foo", []string{"uuid", "name", "created"}, "uuid")
+  for i := 0; i < 10; i++ {
+    u.Row("d3b2aa81", "bar", "now")
+  }
+  b.ReportAllocs()
+  for i := 0; i < b.N; i++ {
+    u.BuildNamed()
+  }
+}
+
+func BenchmarkCreateTable(b *testing.B) {
+  c := CreateTable("foo").
+    Column("uuid", "uuid", PrimaryKey, Default("gen_random_uuid()")).
+    Column("name", "text", NotNull, Unique).
+    Column("bar_uuid", "uuid", References("bar", "uuid"))
+  b.ReportAllocs()
+  for i := 0; i < b.N; i++ {
+    c.BuildNamed()
+  }
+}
+
+func BenchmarkGrant(b *testing.B) {
+  g := Grant("SELECT", "INSERT").OnTable("foo", "bar").To("reporting", "app")
+  b.ReportAllocs()
+  for i := 0; i < b.N; i++ {
+    g.BuildNamed()
+  }
+}
+
+func BenchmarkNotify(b *testing.B) {
+  n := Notify("events", "payload")
+  b.ReportAllocs()
+  for i := 0; i < b.N; i++ {
+    n.BuildNamed()
+  }
+}
+
+func BenchmarkView(b *testing.B) {
+  m := CreateMaterializedView("foo_names", benchQ())
+  b.ReportAllocs()
+  for i := 0; i < b.N; i++ {
+    m.BuildNamed()
+  }
+}
+
+// end
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+type analyzeConfig struct {
+  seqRows float64
+  factor float64
+  minRows float64
+  allowSeq map[string]bool
+}
+
+// sqlFile is the SQL of a query read from a file.
+type sqlFile string
+
+func (s sqlFile) Query() string {
+  return string(s)
+}
+
+// analyze runs every query in the given files or directories through EXPLAIN
+// (ANALYZE, BUFFERS) in a rolled back transaction and reports plan problems.
+// It fails when any query is flagged, for use in CI.
+//
+// A .json file holds a builder stored with goqdsl.Q.MarshalJSON, e.g. by a
+// program dumping the queries it registers, and runs with its own values. A
+// .sql file holds SQL as BuildNamed renders it; its @name parameters take the
+// sample values in the JSON object of -params.
+func analyze(args []string) error {
+
+  fs := flag.NewFlagSet("analyze", flag.ExitOnError)
+  dsn := fs.String("dsn", os.Getenv("DATABASE_URL"), "database connection string")
+  seqRows := fs.Float64("seq-rows", 1000, "flag sequential scans returning at least this many rows")
+  factor := fs.Float64("factor", 10, "flag nodes whose actual rows differ from the estimate by this factor")
+  minRows := fs.Float64("min-rows", 100, "ignore estimate errors on nodes with fewer rows")
+  allow := fs.String("allow-seq", "", "comma separated tables allowed to be scanned sequentially")
+  paramsFile := fs.String("params", "", "JSON object with sample values for the @params of .sql files")
+  fs.Parse(args)
+
+  cfg := analyzeConfig{seqRows: *seqRows, factor: *factor, minRows: *minRows, allowSeq: map[string]bool{}}
+  for _, t := range strings.Split(*allow, ",") {
+    if t != "" {
+      cfg.allowSeq[t] = true
+    }
+  }
+
+  samples := map[string]any{}
+  if *paramsFile != "" {
+    b, err := os.ReadFile(*paramsFile)
+    if err != nil {
+      return err
+    }
+    if err := json.Unmarshal(b, &samples); err != nil {
+      return fmt.Errorf("%s: %w", *paramsFile, err)
+    }
+  }
+
+  files, err := queryFiles(fs.Args())
+  if err != nil {
+    return err
+  }
+  if len(files) == 0 {
+    return errors.New("no .sql or .json files given")
+  }
+
+  ctx := context.Background()
+  conn, err := pgx.Connect(ctx, *dsn)
+  if err != nil {
+    return err
+  }
+  defer conn.Close(ctx)
+
+  flagged := 0
+  for _, f := range files {
+    sql, args, err := load(f, samples)
+    if err != nil {
+      return err
+    }
+
+    plan, err := explain(ctx, conn, sql, args)
+    if err != nil {
+      return fmt.Errorf("%s: %w", f, err)
+    }
+
+    problems := findings(plan, cfg)
+    if len(problems) == 0 {
+      fmt.Printf("ok    %s (%.1f ms)\n", f, plan.ExecutionTime)
+      continue
+    }
+    flagged++
+    fmt.Printf("FAIL  %s (%.1f ms)\n", f, plan.ExecutionTime)
+    for _, p := range problems {
+      fmt.Printf("      %s\n", p)
+    }
+  }
+
+  if flagged > 0 {
+    return fmt.Errorf("%d of %d queries flagged", flagged, len(files))
+  }
+  return nil
+}
+
+func queryFiles(args []string) ([]string, error) {
+  var files []string
+  for _, arg := range args {
+    info, err := os.Stat(arg)
+    if err != nil {
+      return nil, err
+    }
+    if !info.IsDir() {
+      files = append(files, arg)
+      continue
+    }
+    for _, pattern := range []string{"*.sql", "*.json"} {
+      matches, err := filepath.Glob(filepath.Join(arg, pattern))
+      if err != nil {
+        return nil, err
+      }
+      files = append(files, matches...)
+    }
+  }
+  sort.Strings(files)
+  return files, nil
+}
+
+// load reads the query in file with its arguments, see analyze.
+func load(file string, samples map[string]any) (goqdsl.Statement, pgx.NamedArgs, error) {
+
+  b, err := os.ReadFile(file)
+  if err != nil {
+    return nil, nil, err
+  }
+
+  if filepath.Ext(file) == ".json" {
+    q := goqdsl.NewQ()
+    if err := json.Unmarshal(b, q); err != nil {
+      return nil, nil, fmt.Errorf("%s: %w", file, err)
+    }
+    sql, args := goqdsl.NamedArgs(q)
+    return sqlFile(sql), args, nil
+  }
+
+  args := pgx.NamedArgs{}
+  for _, name := range goqdsl.Params(string(b)) {
+    v, ok := samples[name]
+    if !ok {
+      return nil, nil, fmt.Errorf("%s: no sample value for @%s, see -params", file, name)
+    }
+    args[name] = v
+  }
+  return sqlFile(b), args, nil
+}
+
+// explain rolls back, as ANALYZE executes the statement.
+func explain(ctx context.Context, conn *pgx.Conn, s goqdsl.Statement, args pgx.NamedArgs) (*goqdsl.Plan, error) {
+
+  tx, err := conn.Begin(ctx)
+  if err != nil {
+    return nil, err
+  }
+  defer tx.Rollback(ctx)
+
+  var b []byte
+  opts := goqdsl.ExplainOptions{Analyze: true, Buffers: true, Format: goqdsl.JSON}
+  if err := tx.QueryRow(ctx, goqdsl.ExplainQuery(s, opts), args).Scan(&b); err != nil {
+    return nil, err
+  }
+  return goqdsl.ParsePlan(b)
+}
+
+func findings(plan *goqdsl.Plan, cfg analyzeConfig) []string {
+  var problems []string
+  plan.Plan.Walk(func(n goqdsl.PlanNode) {
+
+    actual := n.ActualRows * math.Max(n.ActualLoops, 1)
+    if n.NodeType == "Seq Scan" && !cfg.allowSeq[n.RelationName] && actual >= cfg.seqRows {
+      problems = append(problems, fmt.Sprintf("sequential scan on %s returning %.0f rows", n.RelationName, actual))
+    }
+
+    hi, lo := math.Max(n.ActualRows, n.PlanRows), math.Max(math.Min(n.ActualRows, n.PlanRows), 1)
+    if hi >= cfg.minRows && hi/lo >= cfg.factor {
+      problems = append(problems, fmt.Sprintf("%s%s estimated %.0f rows, got %.0f", n.NodeType, on(n.RelationName), n.PlanRows, n.ActualRows))
+    }
+  })
+  return problems
+}
+
+func on(relation string) string {
+  if relation == "" {
+    return ""
+  }
+  return " on " + relation
+}
+
+// end
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+func TestFindings(t *testing.T) {
+  plan, err := goqdsl.ParsePlan([]byte(`[{
+    "Plan": {
+      "Node Type": "Hash Join", "Plan Rows": 10, "Actual Rows": 5000, "Actual Loops": 1,
+      "Plans": [
+        {"Node Type": "Seq Scan", "Relation Name": "foo", "Plan Rows": 5000, "Actual Rows": 5000, "Actual Loops": 1},
+        {"Node Type": "Seq Scan", "Relation Name": "small", "Plan Rows": 20, "Actual Rows": 20, "Actual Loops": 1},
+        {"Node Type": "Seq Scan", "Relation Name": "bar", "Plan Rows": 2000, "Actual Rows": 2000, "Actual Loops": 1}
+      ]
+    },
+    "Execution Time": 1.5
+  }]`))
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  cfg := analyzeConfig{seqRows: 1000, factor: 10, minRows: 100, allowSeq: map[string]bool{"bar": true}}
+  expected := []string{
+    "Hash Join estimated 10 rows, got 5000",
+    "sequential scan on foo returning 5000 rows",
+  }
+  if problems := findings(plan, cfg); !reflect.DeepEqual(problems, expected) {
+    t.Errorf("expected %v, got %v", expected, problems)
+  }
+}
+
+func TestQueryFiles(t *testing.T) {
+  dir := t.TempDir()
+  for _, name := range []string{"b.sql", "a.sql", "c.json", "notes.txt"} {
+    os.WriteFile(filepath.Join(dir, name), []byte("SELECT 1"), 0o644)
+  }
+
+  files, err := queryFiles([]string{dir})
+  if err != nil {
+    t.Fatal(err)
+  }
+  expected := []string{filepath.Join(dir, "a.sql"), filepath.Join(dir, "b.sql"), filepath.Join(dir, "c.json")}
+  if !reflect.DeepEqual(files, expected) {
+    t.Errorf("expected %v, got %v", expected, files)
+  }
+}
+
+func TestLoad(t *testing.T) {
+  dir := t.TempDir()
+  sqlPath, jsonPath := filepath.Join(dir, "by_name.sql"), filepath.Join(dir, "stored.json")
+  os.WriteFile(sqlPath, []byte("SELECT uuid FROM foo WHERE name = @name AND '@not' <> @name"), 0o644)
+
+  b, err := json.Marshal(goqdsl.NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "bar"}))
+  if err != nil {
+    t.Fatal(err)
+  }
+  os.WriteFile(jsonPath, b, 0o644)
+
+  s, args, err := load(sqlPath, map[string]any{"name": "bar", "unused": 1})
+  if err != nil {
+    t.Fatal(err)
+  }
+  if s.Query() != "SELECT uuid FROM foo WHERE name = @name AND '@not' <> @name" || !reflect.DeepEqual(args, pgx.NamedArgs{"name": "bar"}) {
+    t.Errorf("unexpected query: %s %v", s.Query(), args)
+  }
+  if _, _, err := load(sqlPath, nil); err == nil {
+    t.Error("expected an error for a missing sample value")
+  }
+
+  s, args, err = load(jsonPath, nil)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if s.Query() != "SELECT uuid FROM foo WHERE name = @name " || args["name"] != "bar" {
+    t.Errorf("unexpected stored query: %s %v", s.Query(), args)
+  }
+}
+
+// end
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"go/format"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type column struct {
+  Name string
+  DataType string
+  Nullable bool
+  Enum string
+}
+
+// enum is a PostgreSQL enum type with its labels in sort order.
+type enum struct {
+  Name string
+  Labels []string
+}
+
+type table struct {
+  Name string
+  Columns []column
+  Enums []enum
+}
+
+func gen(args []string) error {
+
+  if len(args) > 0 && args[0] == "scanners" {
+    return genScanners(args[1:])
+  }
+
+  fs := flag.NewFlagSet("gen", flag.ExitOnError)
+  dsn := fs.String("dsn", os.Getenv("DATABASE_URL"), "database connection string")
+  schema := fs.String("schema", "public", "schema to introspect")
+  out := fs.String("out", "models", "output directory, one package per table")
+  only := fs.String("tables", "", "comma separated list of tables (default all)")
+  fs.Parse(args)
+
+  ctx := context.Background()
+  conn, err := pgx.Connect(ctx, *dsn)
+  if err != nil {
+    return err
+  }
+  defer conn.Close(ctx)
+
+  tables, err := introspect(ctx, conn, *schema)
+  if err != nil {
+    return err
+  }
+
+  wanted := map[string]bool{}
+  for _, t := range strings.Split(*only, ",") {
+    if t != "" {
+      wanted[t] = true
+    }
+  }
+
+  for _, t := range tables {
+    if len(wanted) > 0 && !wanted[t.Name] {
+      continue
+    }
+    if err := write(*out, t); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+
+func introspect(ctx context.Context, conn *pgx.Conn, schema string) ([]table, error) {
+
+  enums, err := introspectEnums(ctx, conn, schema)
+  if err != nil {
+    return nil, err
+  }
+
+  rows, err := conn.Query(ctx,
+    "SELECT table_name, column_name, data_type, udt_name, is_nullable = 'YES' "+
+    "FROM information_schema.columns "+
+    "WHERE table_schema = $1 "+
+    "ORDER BY table_name, ordinal_position", schema)
+  if err != nil {
+    return nil, err
+  }
+  defer rows.Close()
+
+  var tables []table
+  for rows.Next() {
+    var name, udt string
+    var c column
+    if err := rows.Scan(&name, &c.Name, &c.DataType, &udt, &c.Nullable); err != nil {
+      return nil, err
+    }
+    if len(tables) == 0 || tables[len(tables)-1].Name != name {
+      tables = append(tables, table{Name: name})
+    }
+    t := &tables[len(tables)-1]
+    if e, ok := enums[udt]; ok && c.DataType == "USER-DEFINED" {
+      c.Enum = udt
+      if !slices.ContainsFunc(t.Enums, func(x enum) bool { return x.Name == udt }) {
+        t.Enums = append(t.Enums, e)
+      }
+    }
+    t.Columns = append(t.Columns, c)
+  }
+  return tables, rows.Err()
+}
+
+// introspectEnums reads the enum types of schema from pg_enum.
+func introspectEnums(ctx context.Context, conn *pgx.Conn, schema string) (map[string]enum, error) {
+
+  rows, err := conn.Query(ctx,
+    "SELECT t.typname, e.enumlabel "+
+    "FROM pg_type t "+
+    "JOIN pg_enum e ON e.enumtypid = t.oid "+
+    "JOIN pg_namespace n ON n.oid = t.typnamespace "+
+    "WHERE n.nspname = $1 "+
+    "ORDER BY t.typname, e.enumsortorder", schema)
+  if err != nil {
+    return nil, err
+  }
+  defer rows.Close()
+
+  enums := map[string]enum{}
+  for rows.Next() {
+    var name, label string
+    if err := rows.Scan(&name, &label); err != nil {
+      return nil, err
+    }
+    e := enums[name]
+    e.Name = name
+    e.Labels = append(e.Labels, label)
+    enums[name] = e
+  }
+  return enums, rows.Err()
+}
+
+func write(dir string, t table) error {
+
+  src, err := render(t)
+  if err != nil {
+    return err
+  }
+
+  pkg := filepath.Join(dir, packageName(t.Name))
+  if err := os.MkdirAll(pkg, 0o755); err != nil {
+    return err
+  }
+  return os.WriteFile(filepath.Join(pkg, packageName(t.Name)+".go"), src, 0o644)
+}
+
+var tmpl = template.Must(template.New("table").Funcs(template.FuncMap{
+  "ident": ident,
+  "goType": goType,
+  "field": field,
+  "valueType": valueType,
+  "label": label,
+}).Parse(`// Code generated by goqdsl gen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- if .Time}}
+	"time"
+{{end}}
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+const Table = {{printf "%q" .Table.Name}}
+{{range .Table.Enums}}
+// {{ident .Name}} is the {{.Name}} enum, {{ident .Name}}Type its name for goqdsl.InEnum.
+type {{ident .Name}} string
+
+const {{ident .Name}}Type = {{printf "%q" .Name}}
+
+const (
+{{- $e := .Name}}
+{{- range .Labels}}
+	{{label $e .}} {{ident $e}} = {{printf "%q" .}}
+{{- end}}
+)
+{{end}}
+const (
+{{- range .Table.Columns}}
+	Col{{ident .Name}} = {{printf "%q" .Name}}
+{{- end}}
+)
+
+// Typed columns, for goqdsl.Q Filter and OrderBy.
+var (
+{{- range .Table.Columns}}
+	{{field $.Table .Name}} = goqdsl.Column[{{valueType .}}]({{printf "%q" .Name}})
+{{- end}}
+)
+
+var Columns = []string{ {{- range $i, $c := .Table.Columns}}{{if $i}}, {{end}}Col{{ident $c.Name}}{{end -}} }
+
+type Row struct {
+{{- range .Table.Columns}}
+	{{ident .Name}} {{goType .}} ` + "`db:\"{{.Name}}\"`" + `
+{{- end}}
+}
+`))
+
+func render(t table) ([]byte, error) {
+
+  usesTime := false
+  for _, c := range t.Columns {
+    if strings.Contains(goType(c), "time.") {
+      usesTime = true
+    }
+  }
+
+  var buf bytes.Buffer
+  err := tmpl.Execute(&buf, map[string]any{
+    "Package": packageName(t.Name),
+    "Table": t,
+    "Time": usesTime,
+  })
+  if err != nil {
+    return nil, err
+  }
+  return format.Source(buf.Bytes())
+}
+
+func packageName(table string) string {
+  pkg := strings.Map(func(r rune) rune {
+    if unicode.IsLetter(r) || unicode.IsDigit(r) {
+      return unicode.ToLower(r)
+    }
+    return -1
+  }, table)
+  if pkg == "" || unicode.IsDigit(rune(pkg[0])) {
+    pkg = "t" + pkg
+  }
+  if token.IsKeyword(pkg) {
+    pkg += "table"
+  }
+  return pkg
+}
+
+func ident(name string) string {
+  var b strings.Builder
+  for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == ' ' || r == '-' }) {
+    b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+  }
+  if b.Len() == 0 || (b.String()[0] >= '0' && b.String()[0] <= '9') {
+    return "C" + b.String()
+  }
+  return b.String()
+}
+
+// field names the typed column of t, avoiding the other generated names,
+// also those of its enums, as for a status column of enum type status.
+func field(t table, name string) string {
+  f := ident(name)
+  switch f {
+  case "Table", "Columns", "Row":
+    return f + "Column"
+  }
+  for _, e := range t.Enums {
+    if f == ident(e.Name) || f == ident(e.Name)+"Type" || slices.ContainsFunc(e.Labels, func(l string) bool { return f == label(e.Name, l) }) {
+      return f + "Column"
+    }
+  }
+  return f
+}
+
+// valueType is the type compared with c, which is not a pointer for nullable
+// columns: NULL is matched with IsNull.
+func valueType(c column) string {
+  return strings.TrimPrefix(goType(c), "*")
+}
+
+// label names the constant for an enum label, which may hold any character.
+func label(enum, l string) string {
+  return ident(enum) + ident(strings.Map(func(r rune) rune {
+    if unicode.IsLetter(r) || unicode.IsDigit(r) {
+      return r
+    }
+    return '_'
+  }, l))
+}
+
+func goType(c column) string {
+
+  var t string
+  switch c.DataType {
+  case "uuid", "text", "character varying", "character", "citext", "inet":
+    t = "string"
+  case "smallint":
+    t = "int16"
+  case "integer":
+    t = "int32"
+  case "bigint":
+    t = "int64"
+  case "boolean":
+    t = "bool"
+  case "real":
+    t = "float32"
+  case "double precision", "numeric":
+    t = "float64"
+  case "date", "timestamp without time zone", "timestamp with time zone":
+    t = "time.Time"
+  case "json", "jsonb", "bytea":
+    return "[]byte"
+  default:
+    if c.Enum == "" {
+      return "any"
+    }
+    t = ident(c.Enum)
+  }
+
+  if c.Nullable {
+    return "*" + t
+  }
+  return t
+}
+
+// end
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+  src, err := render(table{
+    Name: "foo",
+    Columns: []column{
+      {Name: "uuid", DataType: "character varying"},
+      {Name: "name", DataType: "character varying"},
+      {Name: "created", DataType: "timestamp without time zone"},
+      {Name: "parent_uuid", DataType: "uuid", Nullable: true},
+    },
+  })
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  expected := `// Code generated by goqdsl gen. DO NOT EDIT.
+
+package foo
+
+import (
+	"time"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+const Table = "foo"
+
+const (
+	ColUuid       = "uuid"
+	ColName       = "name"
+	ColCreated    = "created"
+	ColParentUuid = "parent_uuid"
+)
+
+// Typed columns, for goqdsl.Q Filter and OrderBy.
+var (
+	Uuid       = goqdsl.Column[string]("uuid")
+	Name       = goqdsl.Column[string]("name")
+	Created    = goqdsl.Column[time.Time]("created")
+	ParentUuid = goqdsl.Column[string]("parent_uuid")
+)
+
+var Columns = []string{ColUuid, ColName, ColCreated, ColParentUuid}
+
+type Row struct {
+	Uuid       string    ` + "`db:\"uuid\"`" + `
+	Name       string    ` + "`db:\"name\"`" + `
+	Created    time.Time ` + "`db:\"created\"`" + `
+	ParentUuid *string   ` + "`db:\"parent_uuid\"`" + `
+}
+`
+  if string(src) != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, src)
+  }
+}
+
+func TestRenderEnum(t *testing.T) {
+  status := enum{Name: "order_status", Labels: []string{"pending", "paid", "on hold"}}
+  src, err := render(table{
+    Name: "orders",
+    Columns: []column{
+      {Name: "status", DataType: "USER-DEFINED", Enum: "order_status"},
+      {Name: "previous", DataType: "USER-DEFINED", Enum: "order_status", Nullable: true},
+    },
+    Enums: []enum{status},
+  })
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  expected := `// Code generated by goqdsl gen. DO NOT EDIT.
+
+package orders
+
+import (
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+const Table = "orders"
+
+// OrderStatus is the order_status enum, OrderStatusType its name for goqdsl.InEnum.
+type OrderStatus string
+
+const OrderStatusType = "order_status"
+
+const (
+	OrderStatusPending OrderStatus = "pending"
+	OrderStatusPaid    OrderStatus = "paid"
+	OrderStatusOnHold  OrderStatus = "on hold"
+)
+
+const (
+	ColStatus   = "status"
+	ColPrevious = "previous"
+)
+
+// Typed columns, for goqdsl.Q Filter and OrderBy.
+var (
+	Status   = goqdsl.Column[OrderStatus]("status")
+	Previous = goqdsl.Column[OrderStatus]("previous")
+)
+
+var Columns = []string{ColStatus, ColPrevious}
+
+type Row struct {
+	Status   OrderStatus  ` + "`db:\"status\"`" + `
+	Previous *OrderStatus ` + "`db:\"previous\"`" + `
+}
+`
+  if string(src) != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, src)
+  }
+}
+
+func TestRenderEnumSameName(t *testing.T) {
+  src, err := render(table{
+    Name: "orders",
+    Columns: []column{{Name: "order_status", DataType: "USER-DEFINED", Enum: "order_status"}},
+    Enums: []enum{{Name: "order_status", Labels: []string{"paid"}}},
+  })
+  if err != nil {
+    t.Fatal(err)
+  }
+  for _, decl := range []string{"type OrderStatus string", "OrderStatusColumn = goqdsl.Column[OrderStatus](\"order_status\")", "OrderStatus OrderStatus `db:\"order_status\"`"} {
+    if !strings.Contains(string(src), decl) {
+      t.Errorf("expected %s in:\n%s", decl, src)
+    }
+  }
+  f, err := parser.ParseFile(token.NewFileSet(), "orders.go", src, 0)
+  if err != nil {
+    t.Fatal(err)
+  }
+  // The file scope keeps one object per name, so a name declared twice
+  // leaves it short.
+  names := 0
+  for _, decl := range f.Decls {
+    for _, spec := range decl.(*ast.GenDecl).Specs {
+      switch spec := spec.(type) {
+      case *ast.ValueSpec:
+        names += len(spec.Names)
+      case *ast.TypeSpec:
+        names++
+      }
+    }
+  }
+  if names != len(f.Scope.Objects) {
+    t.Errorf("%d names declared, %d distinct", names, len(f.Scope.Objects))
+  }
+}
+
+func TestRenderQuotedName(t *testing.T) {
+  src, err := render(table{
+    Name: `Order "Lines"`,
+    Columns: []column{{Name: "line_no", DataType: "integer"}},
+  })
+  if err != nil {
+    t.Fatal(err)
+  }
+  for _, decl := range []string{"package orderlines", `const Table = "Order \"Lines\""`} {
+    if !strings.Contains(string(src), decl) {
+      t.Errorf("expected %s in:\n%s", decl, src)
+    }
+  }
+}
+
+func TestLabel(t *testing.T) {
+  if l := label("order_status", "in-progress/2"); l != "OrderStatusInProgress2" {
+    t.Errorf("expected OrderStatusInProgress2, got %s", l)
+  }
+}
+
+func TestField(t *testing.T) {
+  orders := table{Name: "orders", Enums: []enum{{Name: "order_status", Labels: []string{"paid"}}}}
+  for name, expected := range map[string]string{
+    "parent_uuid": "ParentUuid",
+    "columns": "ColumnsColumn",
+    "order_status": "OrderStatusColumn",
+    "order_status_type": "OrderStatusTypeColumn",
+    "order_status_paid": "OrderStatusPaidColumn",
+  } {
+    if f := field(orders, name); f != expected {
+      t.Errorf("expected %s, got %s", expected, f)
+    }
+  }
+}
+
+func TestPackageName(t *testing.T) {
+  if p := packageName("order_items"); p != "orderitems" {
+    t.Errorf("expected orderitems, got %s", p)
+  }
+  if p := packageName("2024 events"); p != "t2024events" {
+    t.Errorf("expected t2024events, got %s", p)
+  }
+  if p := packageName("type"); p != "typetable" {
+    t.Errorf("expected typetable, got %s", p)
+  }
+}
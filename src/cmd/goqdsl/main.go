@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func usage() {
+  fmt.Fprintf(os.Stderr, "usage: goqdsl <command> [flags]\n\ncommands:\n  gen            generate typed table packages from a database\n  gen scanners   generate ScanColumns methods for //goqdsl:scanner structs\n  analyze        flag plan regressions of .sql and stored .json queries with EXPLAIN ANALYZE\n")
+  os.Exit(2)
+}
+
+func main() {
+
+  if len(os.Args) < 2 {
+    usage()
+  }
+
+  var err error
+  switch os.Args[1] {
+  case "gen":
+    err = gen(os.Args[2:])
+  case "analyze":
+    err = analyze(os.Args[2:])
+  default:
+    usage()
+  }
+
+  if err != nil {
+    fmt.Fprintf(os.Stderr, "goqdsl %s: %v\n", os.Args[1], err)
+    os.Exit(1)
+  }
+}
+
+// end
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const scannerAnnotation = "goqdsl:scanner"
+
+type scannerField struct {
+  Name string
+  Column string
+}
+
+type scannerStruct struct {
+  Name string
+  Fields []scannerField
+}
+
+// genScanners writes goqdsl_scanners.go with a ScanColumns method for every
+// struct in the package annotated with a //goqdsl:scanner comment.
+func genScanners(args []string) error {
+
+  fs := flag.NewFlagSet("gen scanners", flag.ExitOnError)
+  dir := fs.String("dir", ".", "package directory")
+  fs.Parse(args)
+
+  pkg, structs, err := parseScanners(*dir)
+  if err != nil {
+    return err
+  }
+  if len(structs) == 0 {
+    return fmt.Errorf("no structs annotated with //%s in %s", scannerAnnotation, *dir)
+  }
+
+  src, err := renderScanners(pkg, structs)
+  if err != nil {
+    return err
+  }
+  return os.WriteFile(filepath.Join(*dir, "goqdsl_scanners.go"), src, 0o644)
+}
+
+func parseScanners(dir string) (string, []scannerStruct, error) {
+
+  fset := token.NewFileSet()
+  pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+    return !strings.HasSuffix(fi.Name(), "_test.go") && fi.Name() != "goqdsl_scanners.go"
+  }, parser.ParseComments)
+  if err != nil {
+    return "", nil, err
+  }
+
+  var name string
+  var structs []scannerStruct
+  for pkgName, pkg := range pkgs {
+    name = pkgName
+    for _, file := range pkg.Files {
+      for _, decl := range file.Decls {
+        gd, ok := decl.(*ast.GenDecl)
+        if !ok || gd.Tok != token.TYPE {
+          continue
+        }
+        for _, spec := range gd.Specs {
+          ts := spec.(*ast.TypeSpec)
+          st, ok := ts.Type.(*ast.StructType)
+          if !ok || !(annotated(gd.Doc) || annotated(ts.Doc)) {
+            continue
+          }
+          s, err := scannerFields(ts.Name.Name, st)
+          if err != nil {
+            return "", nil, err
+          }
+          structs = append(structs, s)
+        }
+      }
+    }
+  }
+
+  sort.Slice(structs, func(i, j int) bool { return structs[i].Name < structs[j].Name })
+  return name, structs, nil
+}
+
+func annotated(doc *ast.CommentGroup) bool {
+  if doc == nil {
+    return false
+  }
+  for _, c := range doc.List {
+    if strings.TrimSpace(strings.TrimPrefix(c.Text, "//")) == scannerAnnotation {
+      return true
+    }
+  }
+  return false
+}
+
+func scannerFields(name string, st *ast.StructType) (scannerStruct, error) {
+
+  s := scannerStruct{Name: name}
+  seen := map[string]string{}
+  for _, f := range st.Fields.List {
+    if len(f.Names) == 0 {
+      return s, fmt.Errorf("%s: embedded fields are not supported by generated scanners", name)
+    }
+
+    var tag reflect.StructTag
+    if f.Tag != nil {
+      unquoted, err := strconv.Unquote(f.Tag.Value)
+      if err != nil {
+        return s, err
+      }
+      tag = reflect.StructTag(unquoted)
+    }
+
+    for _, n := range f.Names {
+      if !n.IsExported() {
+        continue
+      }
+      column, _ := tag.Lookup("db")
+      if column == "-" {
+        continue
+      }
+      if column, _, _ = strings.Cut(column, ","); column == "" {
+        column = n.Name
+      }
+      // Columns match case-insensitively, as in the reflection path of
+      // goqdslpgx.
+      column = strings.ToLower(column)
+      if other, ok := seen[column]; ok {
+        return s, fmt.Errorf("%s: fields %s and %s map to the same column %s", name, other, n.Name, column)
+      }
+      seen[column] = n.Name
+      s.Fields = append(s.Fields, scannerField{Name: n.Name, Column: column})
+    }
+  }
+  return s, nil
+}
+
+var scannerTmpl = template.Must(template.New("scanners").Parse(`// Code generated by goqdsl gen scanners. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+{{range .Structs}}
+func (r *{{.Name}}) ScanColumns(row pgx.CollectableRow) error {
+	fields := row.FieldDescriptions()
+	dest := make([]any, len(fields))
+	for i, f := range fields {
+		switch strings.ToLower(f.Name) {
+		{{- range .Fields}}
+		case "{{.Column}}":
+			dest[i] = &r.{{.Name}}
+		{{- end}}
+		default:
+			return fmt.Errorf("{{.Name}}: no field for column %s", f.Name)
+		}
+	}
+	return row.Scan(dest...)
+}
+{{end}}`))
+
+func renderScanners(pkg string, structs []scannerStruct) ([]byte, error) {
+  var buf bytes.Buffer
+  if err := scannerTmpl.Execute(&buf, map[string]any{"Package": pkg, "Structs": structs}); err != nil {
+    return nil, err
+  }
+  return format.Source(buf.Bytes())
+}
+
+// end
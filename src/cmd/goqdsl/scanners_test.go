@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenScanners(t *testing.T) {
+  dir := t.TempDir()
+  err := os.WriteFile(filepath.Join(dir, "foo.go"), []byte(`package models
+
+//goqdsl:scanner
+type Foo struct {
+	Uuid    string ` + "`db:\"uuid,pk\"`" + `
+	Name    string
+	Created string ` + "`db:\"CreatedAt\"`" + `
+	Ignored string ` + "`db:\"-\"`" + `
+	hidden  string
+}
+
+type Bar struct {
+	Uuid string
+}
+`), 0o644)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  if err := genScanners([]string{"-dir", dir}); err != nil {
+    t.Fatal(err)
+  }
+  src, err := os.ReadFile(filepath.Join(dir, "goqdsl_scanners.go"))
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  expected := `// Code generated by goqdsl gen scanners. DO NOT EDIT.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (r *Foo) ScanColumns(row pgx.CollectableRow) error {
+	fields := row.FieldDescriptions()
+	dest := make([]any, len(fields))
+	for i, f := range fields {
+		switch strings.ToLower(f.Name) {
+		case "uuid":
+			dest[i] = &r.Uuid
+		case "name":
+			dest[i] = &r.Name
+		case "createdat":
+			dest[i] = &r.Created
+		default:
+			return fmt.Errorf("Foo: no field for column %s", f.Name)
+		}
+	}
+	return row.Scan(dest...)
+}
+`
+  if string(src) != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, src)
+  }
+}
+
+func TestGenScannersSameColumn(t *testing.T) {
+  dir := t.TempDir()
+  err := os.WriteFile(filepath.Join(dir, "foo.go"), []byte(`package models
+
+//goqdsl:scanner
+type Foo struct {
+	Name  string
+	Label string ` + "`db:\"NAME\"`" + `
+}
+`), 0o644)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if err := genScanners([]string{"-dir", dir}); err == nil {
+    t.Error("expected an error for two fields on one column")
+  }
+}
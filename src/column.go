@@ -0,0 +1,242 @@
+package goqdsl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Column is a column name typed with the Go type of its values, as goqdsl gen
+// writes them per table, so the compiler checks both the column and the value:
+//
+//  q.Filter(users.Name.Eq("bar"), users.Age.In(30, 40)).OrderBy(users.Created.Desc())
+type Column[T any] string
+
+func (c Column[T]) Name() string {
+  return string(c)
+}
+
+func (c Column[T]) Eq(v T) Cond {
+  return Cond{column: string(c), op: "=", values: []any{v}}
+}
+
+// In matches any of vs. Without values it matches nothing.
+func (c Column[T]) In(vs ...T) Cond {
+  values := make([]any, len(vs))
+  for i, v := range vs {
+    values[i] = v
+  }
+  return Cond{column: string(c), op: "IN", values: values}
+}
+
+// EqParam compares with a hole for Prepare, bound per execution.
+func (c Column[T]) EqParam(name string) Cond {
+  return Cond{column: string(c), op: "=", values: []any{Param(name)}}
+}
+
+func (c Column[T]) Asc() Order {
+  return Asc(string(c))
+}
+
+func (c Column[T]) Desc() Order {
+  return Desc(string(c))
+}
+
+// Cond is a condition with bound values, added with Q.Filter: a comparison
+// of a column, or And, Or or Not over other conditions.
+type Cond struct {
+  column string
+  op string
+  values []any
+  zone string
+  cast string
+  conds []Cond
+  err error
+}
+
+// Cast casts each value of c to typ, e.g. to an enum type as in
+// status = @status::order_status. If typ is not a type name the cast is left
+// out and CheckIdentifiers reports it.
+func (c Cond) Cast(typ string) Cond {
+  if !castType.MatchString(typ) {
+    c.err = fmt.Errorf("goqdsl: invalid cast type %q", typ)
+    return c
+  }
+  c.cast = typ
+  return c
+}
+
+// ops are the operators a Cond may hold, also when loaded from JSON. RANGE
+// holds a half-open range [from, to).
+var ops = map[string]bool{
+  "=": true, "<>": true, "<": true, "<=": true, ">": true, ">=": true,
+  "LIKE": true, "ILIKE": true, "IN": true, "RANGE": true,
+  "IS NULL": true, "IS NOT NULL": true, "AND": true, "OR": true, "NOT": true,
+}
+
+// And matches when all of conds do, always without conds.
+func And(conds ...Cond) Cond {
+  return Cond{op: "AND", conds: conds}
+}
+
+// Or matches when any of conds does, never without conds.
+func Or(conds ...Cond) Cond {
+  return Cond{op: "OR", conds: conds}
+}
+
+func Not(c Cond) Cond {
+  return Cond{op: "NOT", conds: []Cond{c}}
+}
+
+// write renders c, passing each value and its column to arg.
+func (c Cond) write(sb *strings.Builder, arg func(column string, v any)) {
+
+  if c.cast != "" {
+    inner := arg
+    arg = func(column string, v any) {
+      inner(column, v)
+      sb.WriteString("::")
+      sb.WriteString(c.cast)
+    }
+  }
+
+  switch {
+  case c.op == "AND" && len(c.conds) == 0:
+    sb.WriteString("TRUE")
+    return
+  case c.op == "IN" && len(c.values) == 0, c.op == "OR" && len(c.conds) == 0:
+    sb.WriteString("FALSE")
+    return
+  }
+
+  lhs := c.column
+  if c.zone != "" {
+    lhs += " AT TIME ZONE " + quote(c.zone)
+  }
+
+  switch c.op {
+  case "AND", "OR":
+    sb.WriteByte('(')
+    for i, sub := range c.conds {
+      if i > 0 {
+        sb.WriteByte(' ')
+        sb.WriteString(c.op)
+        sb.WriteByte(' ')
+      }
+      sub.write(sb, arg)
+    }
+    sb.WriteByte(')')
+  case "NOT":
+    sb.WriteString("NOT (")
+    c.conds[0].write(sb, arg)
+    sb.WriteByte(')')
+  case "IS NULL", "IS NOT NULL":
+    sb.WriteString(lhs)
+    sb.WriteByte(' ')
+    sb.WriteString(c.op)
+  case "IN":
+    sb.WriteString(lhs)
+    sb.WriteString(" IN (")
+    for i, v := range c.values {
+      if i > 0 {
+        sb.WriteString(", ")
+      }
+      arg(c.column, v)
+    }
+    sb.WriteByte(')')
+  case "RANGE":
+    sb.WriteString(lhs)
+    sb.WriteString(" >= ")
+    arg(c.column, c.values[0])
+    sb.WriteString(" AND   ")
+    sb.WriteString(lhs)
+    sb.WriteString(" < ")
+    arg(c.column, c.values[1])
+  default:
+    sb.WriteString(lhs)
+    sb.WriteByte(' ')
+    sb.WriteString(c.op)
+    sb.WriteByte(' ')
+    arg(c.column, c.values[0])
+  }
+}
+
+// valueCount counts the values of c and the conditions under it.
+func (c Cond) valueCount() int {
+  n := len(c.values)
+  for _, sub := range c.conds {
+    n += sub.valueCount()
+  }
+  return n
+}
+
+// size estimates the length of c rendered with parameters, to size builders.
+func (c Cond) size() int {
+  n := 2*len(c.column) + len(c.op) + len(c.zone) + 8
+  for range c.values {
+    n += len(c.column) + len(c.cast) + 8
+  }
+  for _, sub := range c.conds {
+    n += sub.size() + len(c.op) + 2
+  }
+  return n
+}
+
+// columns calls fn for each column c compares.
+// check returns the first error recorded on c or its conditions.
+func (c Cond) check() error {
+  if c.err != nil {
+    return c.err
+  }
+  for _, sub := range c.conds {
+    if err := sub.check(); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+
+func (c Cond) columns(fn func(column string) error) error {
+  if c.column != "" {
+    if err := fn(c.column); err != nil {
+      return err
+    }
+  }
+  for _, sub := range c.conds {
+    if err := sub.columns(fn); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+
+// Order is one ORDER BY item, added with Q.OrderBy.
+type Order struct {
+  column string
+  desc bool
+}
+
+func Asc(column string) Order {
+  return Order{column: column}
+}
+
+func Desc(column string) Order {
+  return Order{column: column, desc: true}
+}
+
+func (o Order) String() string {
+  if o.desc {
+    return o.column + " DESC"
+  }
+  return o.column + " ASC"
+}
+
+func (o Order) write(sb *strings.Builder) {
+  sb.WriteString(o.column)
+  if o.desc {
+    sb.WriteString(" DESC")
+  } else {
+    sb.WriteString(" ASC")
+  }
+}
+
+// end
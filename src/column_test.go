@@ -0,0 +1,54 @@
+package goqdsl
+
+import (
+	"testing"
+	"time"
+)
+
+var (
+  fooName = Column[string]("name")
+  fooAge = Column[int]("age")
+  fooCreated = Column[time.Time]("created")
+)
+
+func TestColumnFilter(t *testing.T) {
+  q := NewQ().Select("uuid", "name").From("foo").
+    Where(map[string]string{"name": "bar"}).
+    Filter(fooName.Eq("baz"), fooAge.In(30, 40)).
+    OrderBy(fooCreated.Desc(), fooName.Asc())
+
+  sql, args := q.BuildNamed()
+  expected := "SELECT uuid, name FROM foo WHERE name = @name AND   name = @name_2 AND   age IN (@age, @age_2) ORDER BY created DESC, name ASC "
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+  if len(args) != 4 || args["name"] != "bar" || args["name_2"] != "baz" || args["age"] != 30 || args["age_2"] != 40 {
+    t.Errorf("unexpected args: %v", args)
+  }
+
+  sql, pargs := q.BuildPositional()
+  expected = "SELECT uuid, name FROM foo WHERE name = $1 AND   name = $2 AND   age IN ($3, $4) ORDER BY created DESC, name ASC "
+  if sql != expected || len(pargs) != 4 || pargs[1] != "baz" || pargs[3] != 40 {
+    t.Errorf("unexpected positional build: %s %v", sql, pargs)
+  }
+
+  expected = "SELECT uuid, name FROM foo WHERE name = bar AND   name = 'baz' AND   age IN (30, 40) ORDER BY created DESC, name ASC "
+  if sql := q.Query(); sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+}
+
+func TestColumnInEmpty(t *testing.T) {
+  sql, args := NewQ().Select("uuid").From("foo").Filter(fooAge.In()).BuildNamed()
+  if sql != "SELECT uuid FROM foo WHERE FALSE " || len(args) != 0 {
+    t.Errorf("unexpected build: %s %v", sql, args)
+  }
+}
+
+func TestColumnIdentifiers(t *testing.T) {
+  if err := CheckIdentifiers(NewQ().Select("uuid").From("foo").OrderBy(Desc("created; DROP TABLE foo"))); err == nil {
+    t.Error("expected an identifier error")
+  }
+}
+
+// end
@@ -0,0 +1,67 @@
+package goqdsl
+
+import (
+	"slices"
+)
+
+// MergeWhere adds the conditions of other to those of q: its where values,
+// replacing q's for the same column, its null checks and its typed
+// conditions.
+func (q *Q) MergeWhere(other *Q) *Q {
+  q.mutate()
+  if len(other.criteria) > 0 {
+    criteria := make(map[string]string, len(q.criteria)+len(other.criteria))
+    for k, v := range q.criteria {
+      criteria[k] = v
+    }
+    for k, v := range other.criteria {
+      criteria[k] = v
+    }
+    q.criteria = criteria
+  }
+  q.nulls = append(q.nulls, other.nulls...)
+  q.conds = append(q.conds, other.conds...)
+  return q
+}
+
+// Compose returns a new Q specializing base with overrides, leaving both
+// unchanged. The select list, table, INTO, grouping, ordering, limit and
+// offset of overrides replace those of base where set; joins of overrides
+// that base lacks are added after its own; conditions are merged as by
+// MergeWhere.
+func Compose(base, overrides *Q) *Q {
+  q := base.Clone()
+  if len(overrides.fields) > 0 {
+    q.fields = append([]string(nil), overrides.fields...)
+  }
+  if overrides.from != "" {
+    q.from, q.alias = overrides.from, overrides.alias
+  }
+  if overrides.into != "" {
+    q.into = overrides.into
+  }
+  for _, j := range overrides.joins {
+    if !slices.Contains(q.joins, j) {
+      q.joins = append(q.joins, j)
+    }
+  }
+  q.MergeWhere(overrides)
+  for sql, err := range overrides.exprs {
+    q.trust(Expr{sql: sql, err: err})
+  }
+  if len(overrides.groupBy) > 0 {
+    q.groupBy = append([]string(nil), overrides.groupBy...)
+  }
+  if len(overrides.order) > 0 {
+    q.order = append([]Order(nil), overrides.order...)
+  }
+  if overrides.limit > 0 {
+    q.limit = overrides.limit
+  }
+  if overrides.offset > 0 {
+    q.offset = overrides.offset
+  }
+  return q
+}
+
+// end
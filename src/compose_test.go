@@ -0,0 +1,42 @@
+package goqdsl
+
+import (
+	"testing"
+)
+
+func TestMergeWhere(t *testing.T) {
+  q := NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "bar", "kind": "a"})
+  other := NewQ().Where(map[string]string{"kind": "b"}).IsNull("deleted_at").Filter(Column[int]("age").Eq(42))
+
+  expected := "SELECT uuid FROM foo WHERE kind = b AND   name = bar AND   deleted_at IS NULL AND   age = 42 "
+  if sql := q.MergeWhere(other).Query(); sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+  if other.criteria["kind"] != "b" || len(other.criteria) != 1 {
+    t.Errorf("MergeWhere changed other: %v", other.criteria)
+  }
+}
+
+func TestCompose(t *testing.T) {
+  orders := T("orders").As("o")
+  base := NewQ().Select("u.uuid", "u.name").FromTable(T("users").As("u")).
+    InnerJoin([]Join{orders.On("u.uuid", "o.user_uuid")}).
+    IsNull("u.deleted_at").OrderBy(Asc("u.name")).Limit(50).Freeze()
+
+  overrides := NewQ().Select("u.uuid").
+    InnerJoin([]Join{orders.On("u.uuid", "o.user_uuid"), T("payments").As("p").On("o.uuid", "p.order_uuid")}).
+    Where(map[string]string{"p.status": "open"}).OrderBy(Desc("o.created"))
+
+  expected := "SELECT u.uuid FROM users u INNER JOIN orders o ON u.uuid = o.user_uuid INNER JOIN payments p ON o.uuid = p.order_uuid " +
+    "WHERE p.status = open AND   u.deleted_at IS NULL ORDER BY o.created DESC LIMIT 50 "
+  if sql := Compose(base, overrides).Query(); sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+
+  expected = "SELECT u.uuid, u.name FROM users u INNER JOIN orders o ON u.uuid = o.user_uuid WHERE u.deleted_at IS NULL ORDER BY u.name ASC LIMIT 50 "
+  if sql := base.Query(); sql != expected {
+    t.Errorf("Compose changed base: %s", sql)
+  }
+}
+
+// end
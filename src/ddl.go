@@ -0,0 +1,172 @@
+package goqdsl
+
+import (
+	"strings"
+)
+
+type ColumnOption func(*columnDef)
+
+type columnDef struct {
+  name string
+  kind string
+  primaryKey bool
+  notNull bool
+  unique bool
+  def string
+  references string
+}
+
+var (
+  PrimaryKey ColumnOption = func(c *columnDef) { c.primaryKey = true }
+  NotNull ColumnOption = func(c *columnDef) { c.notNull = true }
+  Unique ColumnOption = func(c *columnDef) { c.unique = true }
+)
+
+func Default(expr string) ColumnOption {
+  return func(c *columnDef) { c.def = expr }
+}
+
+func References(table, column string) ColumnOption {
+  return func(c *columnDef) { c.references = table + " (" + column + ")" }
+}
+
+type CreateTableQ struct {
+  table string
+  ifNotExists bool
+  columns []columnDef
+}
+
+func CreateTable(t string) *CreateTableQ {
+  return &CreateTableQ{table: t}
+}
+
+func (c *CreateTableQ) Column(name, kind string, opts ...ColumnOption) *CreateTableQ {
+  col := columnDef{name: name, kind: kind}
+  for _, opt := range opts {
+    opt(&col)
+  }
+  c.columns = append(c.columns, col)
+  return c
+}
+
+func (c *CreateTableQ) IfNotExists() *CreateTableQ {
+  c.ifNotExists = true
+  return c
+}
+
+func (c *CreateTableQ) BuildNamed() (string, map[string]any) {
+  return c.Query(), nil
+}
+
+func (c *CreateTableQ) BuildPositional() (string, []any) {
+  return c.Query(), nil
+}
+
+func (c *CreateTableQ) Query() string {
+
+  var sb strings.Builder
+  sb.Grow(32 + len(c.table) + 48*len(c.columns))
+
+  sb.WriteString("CREATE TABLE ")
+  if c.ifNotExists {
+    sb.WriteString("IF NOT EXISTS ")
+  }
+  sb.WriteString(c.table)
+  sb.WriteString(" (")
+  for i, col := range c.columns {
+    if i > 0 {
+      sb.WriteString(", ")
+    }
+    col.writeDefinition(&sb)
+  }
+  sb.WriteByte(')')
+  return sb.String()
+}
+
+type CreateTableAsQ struct {
+  table string
+  query *Q
+  ifNotExists bool
+  withNoData bool
+}
+
+func CreateTableAs(t string, q *Q) *CreateTableAsQ {
+  return &CreateTableAsQ{table: t, query: q}
+}
+
+// Source returns the query the table is created from.
+func (c *CreateTableAsQ) Source() *Q {
+  return c.query
+}
+
+// WithSource returns a copy of c creating the table from q instead.
+func (c *CreateTableAsQ) WithSource(q *Q) *CreateTableAsQ {
+  d := *c
+  d.query = q
+  return &d
+}
+
+func (c *CreateTableAsQ) IfNotExists() *CreateTableAsQ {
+  c.ifNotExists = true
+  return c
+}
+
+func (c *CreateTableAsQ) WithNoData() *CreateTableAsQ {
+  c.withNoData = true
+  return c
+}
+
+// PostgreSQL does not take bind parameters in CREATE TABLE AS, so the values
+// of the query are rendered as quoted literals, see inlined.
+func (c *CreateTableAsQ) BuildNamed() (string, map[string]any) {
+  return c.Query(), nil
+}
+
+func (c *CreateTableAsQ) BuildPositional() (string, []any) {
+  return c.Query(), nil
+}
+
+func (c *CreateTableAsQ) Query() string {
+
+  sql := "CREATE TABLE "
+  if c.ifNotExists {
+    sql += "IF NOT EXISTS "
+  }
+  sql += c.table + " AS " + inlined(c.query)
+  if c.withNoData {
+    sql += " WITH NO DATA"
+  }
+  return sql
+}
+
+// inlined renders q for DDL that takes no bind parameters, with its values as
+// SQL literals like ToSQL.
+func inlined(q *Q) string {
+  return strings.TrimSpace(ToSQL(q))
+}
+
+func (c columnDef) writeDefinition(sb *strings.Builder) {
+
+  sb.WriteString(c.name)
+  sb.WriteByte(' ')
+  sb.WriteString(c.kind)
+  if c.primaryKey {
+    sb.WriteString(" PRIMARY KEY")
+  }
+  if c.notNull {
+    sb.WriteString(" NOT NULL")
+  }
+  if c.unique {
+    sb.WriteString(" UNIQUE")
+  }
+  if c.def != "" {
+    sb.WriteString(" DEFAULT ")
+    sb.WriteString(c.def)
+  }
+  if c.references != "" {
+    sb.WriteString(" REFERENCES ")
+    sb.WriteString(c.references)
+  }
+}
+
+// end
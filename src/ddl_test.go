@@ -0,0 +1,59 @@
+package goqdsl
+
+import (
+	"testing"
+)
+
+func TestCreateTable(t *testing.T) {
+  sql := CreateTable("foo").
+    Column("uuid", "varchar(36)", PrimaryKey, NotNull, Default("gen_random_uuid()")).
+    Column("name", "varchar(100)", NotNull, Unique).
+    Column("created", "timestamp", NotNull, Default("now()")).
+    IfNotExists().
+    Query()
+
+  expected := "CREATE TABLE IF NOT EXISTS foo (" +
+    "uuid varchar(36) PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(), " +
+    "name varchar(100) NOT NULL UNIQUE, " +
+    "created timestamp NOT NULL DEFAULT now())"
+
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+}
+
+func TestCreateTableReferences(t *testing.T) {
+  sql := CreateTable("bar").
+    Column("foo_uuid", "varchar(36)", NotNull, References("foo", "uuid")).
+    Query()
+
+  expected := "CREATE TABLE bar (foo_uuid varchar(36) NOT NULL REFERENCES foo (uuid))"
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+}
+
+func TestCreateTableAs(t *testing.T) {
+  q := NewQ().Select("uuid", "name").From("foo").Where(map[string]string{"name": "bar"})
+
+  sql := CreateTableAs("foo_snapshot", q).IfNotExists().Query()
+  expected := "CREATE TABLE IF NOT EXISTS foo_snapshot AS SELECT uuid, name FROM foo WHERE name = 'bar'"
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+
+  q = NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "x' OR 1=1 --"})
+  sql = CreateTableAs("foo_snapshot", q).Query()
+  expected = "CREATE TABLE foo_snapshot AS SELECT uuid FROM foo WHERE name = 'x'' OR 1=1 --'"
+  if sql != expected {
+    t.Errorf("expected a quoted literal:\n%s\ngot:\n%s", expected, sql)
+  }
+}
+
+func TestSelectInto(t *testing.T) {
+  sql := NewQ().Select("uuid", "name").Into("foo_snapshot").From("foo").Query()
+  expected := "SELECT uuid, name INTO foo_snapshot FROM foo "
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+}
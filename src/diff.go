@@ -0,0 +1,140 @@
+package goqdsl
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// A Change is one difference found by Diff. Want is empty for something
+// extra, Got for something missing.
+type Change struct {
+  Clause string // e.g. WHERE, FROM, INNER JOIN or @name for an argument
+  Want string
+  Got string
+}
+
+func (c Change) String() string {
+  switch {
+  case c.Want == "":
+    return c.Clause + ": extra " + c.Got
+  case c.Got == "":
+    return c.Clause + ": missing " + c.Want
+  }
+  return c.Clause + ": " + c.Want + " became " + c.Got
+}
+
+// Diff compares two builders clause by clause, treating the WHERE predicates
+// as a set, and then their argument values.
+func Diff(want, got Builder) []Change {
+
+  wantSQL, wantArgs := want.BuildNamed()
+  gotSQL, gotArgs := got.BuildNamed()
+  changes := diffClauses(wantSQL, gotSQL)
+
+  names := map[string]bool{}
+  for k := range wantArgs {
+    names[k] = true
+  }
+  for k := range gotArgs {
+    names[k] = true
+  }
+  sorted := make([]string, 0, len(names))
+  for k := range names {
+    sorted = append(sorted, k)
+  }
+  sort.Strings(sorted)
+
+  for _, k := range sorted {
+    w, inWant := wantArgs[k]
+    g, inGot := gotArgs[k]
+    if inWant && inGot && reflect.DeepEqual(w, g) {
+      continue
+    }
+    c := Change{Clause: "@" + k}
+    if inWant {
+      c.Want = fmt.Sprintf("%#v", w)
+    }
+    if inGot {
+      c.Got = fmt.Sprintf("%#v", g)
+    }
+    changes = append(changes, c)
+  }
+  return changes
+}
+
+// DiffSQL compares expected SQL with literal values to the ToSQL output of
+// got.
+func DiffSQL(want string, got Builder) []Change {
+  return diffClauses(want, ToSQL(got))
+}
+
+type clause struct {
+  name string
+  body string
+}
+
+func diffClauses(want, got string) []Change {
+
+  w, g := clausesOf(want), clausesOf(got)
+  var missing, extra []clause
+  for _, c := range w {
+    if i := indexOf(g, c); i >= 0 {
+      g = append(g[:i], g[i+1:]...)
+    } else {
+      missing = append(missing, c)
+    }
+  }
+  extra = g
+
+  var changes []Change
+  for _, m := range missing {
+    c := Change{Clause: m.name, Want: m.body}
+    for i, e := range extra {
+      if e.name == m.name {
+        c.Got = e.body
+        extra = append(extra[:i], extra[i+1:]...)
+        break
+      }
+    }
+    changes = append(changes, c)
+  }
+  for _, e := range extra {
+    changes = append(changes, Change{Clause: e.name, Got: e.body})
+  }
+  return changes
+}
+
+func indexOf(cs []clause, c clause) int {
+  for i, x := range cs {
+    if x == c {
+      return i
+    }
+  }
+  return -1
+}
+
+// clausesOf splits sql into its clauses, with AND predicates filed under
+// WHERE. Subqueries are split as well and their clauses prefixed with the
+// indentation depth.
+func clausesOf(sql string) []clause {
+  var cs []clause
+  for _, line := range strings.Split(pretty(sql), "\n") {
+    depth := (len(line) - len(strings.TrimLeft(line, " "))) / 2
+    line = strings.TrimSpace(line)
+    name := clauseAt(line, 0, ' ')
+    body := strings.TrimSpace(strings.TrimPrefix(line, name))
+    if name == "AND" {
+      name = "WHERE"
+      depth--
+    }
+    if depth > 0 {
+      name = strings.Repeat(">", depth) + " " + name
+    }
+    cs = append(cs, clause{name: name, body: body})
+  }
+  return cs
+}
+
+// end
@@ -0,0 +1,56 @@
+package goqdsl
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+  want := NewQ().Select("uuid", "name").From("foo").Where(map[string]string{"name": "bar", "active": "true"})
+  got := NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "baz", "deleted": "false"})
+
+  expected := []Change{
+    {Clause: "SELECT", Want: "uuid, name", Got: "uuid"},
+    {Clause: "WHERE", Want: "active = @active", Got: "deleted = @deleted"},
+    {Clause: "@active", Want: `"true"`},
+    {Clause: "@deleted", Got: `"false"`},
+    {Clause: "@name", Want: `"bar"`, Got: `"baz"`},
+  }
+  if changes := Diff(want, got); !reflect.DeepEqual(changes, expected) {
+    t.Errorf("expected:\n%v\ngot:\n%v", expected, changes)
+  }
+
+  if changes := Diff(want, want.Clone()); len(changes) != 0 {
+    t.Errorf("expected no changes, got %v", changes)
+  }
+}
+
+func TestDiffSQL(t *testing.T) {
+  got := NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "bar", "uuid": "1"})
+
+  changes := DiffSQL("SELECT uuid FROM foo WHERE uuid = '1' AND name = 'baz'", got)
+  expected := []Change{{Clause: "WHERE", Want: "name = 'baz'", Got: "name = 'bar'"}}
+  if !reflect.DeepEqual(changes, expected) {
+    t.Errorf("expected:\n%v\ngot:\n%v", expected, changes)
+  }
+  if s := changes[0].String(); s != "WHERE: name = 'baz' became name = 'bar'" {
+    t.Errorf("unexpected string: %s", s)
+  }
+}
+func TestDiffSQLRange(t *testing.T) {
+  from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+  got := NewQ().Select("uuid").From("events").Filter(BetweenTime("created", from, from.AddDate(0, 1, 0)))
+
+  changes := DiffSQL("SELECT uuid FROM events WHERE created >= '2024-01-01T00:00:00Z' AND created < '2024-03-01T00:00:00Z'", got)
+  expected := []Change{{
+    Clause: "WHERE",
+    Want: "created >= '2024-01-01T00:00:00Z' AND created < '2024-03-01T00:00:00Z'",
+    Got: "created >= '2024-01-01T00:00:00Z' AND created < '2024-02-01T00:00:00Z'",
+  }}
+  if !reflect.DeepEqual(changes, expected) {
+    t.Errorf("expected:\n%v\ngot:\n%v", expected, changes)
+  }
+}
+
+// end
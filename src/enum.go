@@ -0,0 +1,18 @@
+package goqdsl
+
+// EqEnum compares column with v, a value of a Go string type for the
+// PostgreSQL enum enumType, cast to it: status = @status::order_status.
+func EqEnum[E ~string](column, enumType string, v E) Cond {
+  return Cond{column: column, op: "=", values: []any{string(v)}}.Cast(enumType)
+}
+
+// InEnum matches column against any of values, cast to enumType.
+func InEnum[E ~string](column, enumType string, values ...E) Cond {
+  vs := make([]any, len(values))
+  for i, v := range values {
+    vs[i] = string(v)
+  }
+  return Cond{column: column, op: "IN", values: vs}.Cast(enumType)
+}
+
+// end
@@ -0,0 +1,56 @@
+package goqdsl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type orderStatus string
+
+const (
+  orderPending orderStatus = "pending"
+  orderPaid orderStatus = "paid"
+)
+
+func TestInEnum(t *testing.T) {
+  q := NewQ().Select("uuid").From("orders").Filter(InEnum("status", "order_status", orderPending, orderPaid), EqEnum("previous", "shop.order_status", orderPending))
+
+  sql, args := q.BuildNamed()
+  expected := "SELECT uuid FROM orders WHERE status IN (@status::order_status, @status_2::order_status) AND   previous = @previous::shop.order_status "
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+  if args["status"] != "pending" || args["status_2"] != "paid" {
+    t.Errorf("expected plain strings, got %v", args)
+  }
+
+  expected = "SELECT uuid FROM orders WHERE status IN ('pending'::order_status, 'paid'::order_status) AND   previous = 'pending'::shop.order_status "
+  if sql := q.Query(); sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+
+  b, err := json.Marshal(q)
+  if err != nil {
+    t.Fatal(err)
+  }
+  loaded := NewQ()
+  if err := json.Unmarshal(b, loaded); err != nil || loaded.Query() != q.Query() {
+    t.Errorf("expected %s, got %s (%v)", q.Query(), loaded.Query(), err)
+  }
+}
+
+func TestCastInvalid(t *testing.T) {
+  q := NewQ().Select("uuid").From("foo").Filter(Or(fooName.Eq("bar").Cast("text); DROP TABLE foo; --")))
+  if err := CheckIdentifiers(q); err == nil || !strings.Contains(err.Error(), "invalid cast type") {
+    t.Errorf("expected an invalid cast error, got %v", err)
+  }
+  if sql, _ := q.BuildNamed(); strings.Contains(sql, "DROP") {
+    t.Errorf("expected the cast left out, got %s", sql)
+  }
+  if _, err := json.Marshal(q); err == nil {
+    t.Error("expected MarshalJSON to report the invalid cast")
+  }
+}
+
+// end
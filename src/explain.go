@@ -0,0 +1,105 @@
+package goqdsl
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type ExplainFormat string
+
+const (
+  Text ExplainFormat = "TEXT"
+  JSON ExplainFormat = "JSON"
+)
+
+type ExplainOptions struct {
+  Analyze bool
+  Buffers bool
+  Verbose bool
+  Format ExplainFormat
+}
+
+type Plan struct {
+  Plan PlanNode `json:"Plan"`
+  PlanningTime float64 `json:"Planning Time"`
+  ExecutionTime float64 `json:"Execution Time"`
+}
+
+type PlanNode struct {
+  NodeType string `json:"Node Type"`
+  RelationName string `json:"Relation Name"`
+  Alias string `json:"Alias"`
+  IndexName string `json:"Index Name"`
+  StartupCost float64 `json:"Startup Cost"`
+  TotalCost float64 `json:"Total Cost"`
+  PlanRows float64 `json:"Plan Rows"`
+  ActualRows float64 `json:"Actual Rows"`
+  ActualLoops float64 `json:"Actual Loops"`
+  Plans []PlanNode `json:"Plans"`
+}
+
+// Walk calls fn for the node and all of its children, depth first.
+func (n PlanNode) Walk(fn func(PlanNode)) {
+  fn(n)
+  for _, child := range n.Plans {
+    child.Walk(fn)
+  }
+}
+
+func (n PlanNode) UsesIndex(name string) bool {
+  found := false
+  n.Walk(func(c PlanNode) {
+    if c.IndexName == name {
+      found = true
+    }
+  })
+  return found
+}
+
+func (n PlanNode) SeqScans() []string {
+  var tables []string
+  n.Walk(func(c PlanNode) {
+    if c.NodeType == "Seq Scan" {
+      tables = append(tables, c.RelationName)
+    }
+  })
+  return tables
+}
+
+func ExplainQuery(s Statement, opts ExplainOptions) string {
+
+  var options []string
+  if opts.Analyze {
+    options = append(options, "ANALYZE")
+  }
+  if opts.Buffers {
+    options = append(options, "BUFFERS")
+  }
+  if opts.Verbose {
+    options = append(options, "VERBOSE")
+  }
+  if opts.Format != "" {
+    options = append(options, "FORMAT "+string(opts.Format))
+  }
+
+  sql := "EXPLAIN "
+  if len(options) > 0 {
+    sql += "(" + strings.Join(options, ", ") + ") "
+  }
+  return sql + strings.TrimSpace(s.Query())
+}
+
+// ParsePlan parses the output of EXPLAIN (FORMAT JSON).
+func ParsePlan(b []byte) (*Plan, error) {
+  var plans []Plan
+  if err := json.Unmarshal(b, &plans); err != nil {
+    return nil, err
+  }
+  if len(plans) == 0 {
+    return nil, fmt.Errorf("explain: empty plan")
+  }
+  return &plans[0], nil
+}
+
+// end
@@ -0,0 +1,46 @@
+package goqdsl
+
+import (
+	"testing"
+)
+
+func TestExplainQuery(t *testing.T) {
+  q := NewQ().Select("uuid", "name").From("foo")
+
+  sql := ExplainQuery(q, ExplainOptions{Analyze: true, Buffers: true, Format: JSON})
+  expected := "EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) SELECT uuid, name FROM foo"
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+
+  if sql := ExplainQuery(q, ExplainOptions{}); sql != "EXPLAIN SELECT uuid, name FROM foo" {
+    t.Errorf("unexpected sql: %s", sql)
+  }
+}
+
+func TestParsePlan(t *testing.T) {
+  plan, err := ParsePlan([]byte(`[{
+    "Plan": {
+      "Node Type": "Nested Loop", "Total Cost": 16.5, "Plan Rows": 1,
+      "Plans": [
+        {"Node Type": "Index Scan", "Relation Name": "foo", "Index Name": "foo_name_key", "Actual Rows": 1},
+        {"Node Type": "Seq Scan", "Relation Name": "bar", "Actual Rows": 40}
+      ]
+    },
+    "Planning Time": 0.1,
+    "Execution Time": 0.2
+  }]`))
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  if plan.Plan.NodeType != "Nested Loop" || plan.Plan.TotalCost != 16.5 || plan.ExecutionTime != 0.2 {
+    t.Errorf("unexpected plan: %+v", plan)
+  }
+  if !plan.Plan.UsesIndex("foo_name_key") {
+    t.Error("expected plan to use foo_name_key")
+  }
+  if scans := plan.Plan.SeqScans(); len(scans) != 1 || scans[0] != "bar" {
+    t.Errorf("expected seq scan on bar, got %v", scans)
+  }
+}
@@ -0,0 +1,85 @@
+package goqdsl
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// filterOps maps the operators of filter tags to SQL.
+var filterOps = map[string]string{
+  "eq": "=", "ne": "<>", "lt": "<", "lte": "<=", "gt": ">", "gte": ">=",
+  "like": "LIKE", "ilike": "ILIKE", "in": "IN",
+}
+
+// Filters returns a condition for each field of the struct v, or pointer to
+// one, tagged filter:"column,op", skipping nil fields:
+//
+//  type fooFilter struct {
+//    Name *string `filter:"name,ilike"`
+//    MinAge *int `filter:"age,gte"`
+//    Kinds []string `filter:"kind,in"`
+//  }
+//
+// The op is one of eq, the default, ne, lt, lte, gt, gte, like, ilike and, for
+// slices, in. Values bind as they are, so like patterns carry their own
+// wildcards. Filters fails on a bad tag.
+func Filters(v any) ([]Cond, error) {
+
+  rv := reflect.Indirect(reflect.ValueOf(v))
+  if rv.Kind() != reflect.Struct {
+    return nil, fmt.Errorf("goqdsl: filter %T is not a struct", v)
+  }
+
+  var conds []Cond
+  t := rv.Type()
+  for i := 0; i < t.NumField(); i++ {
+    tag, ok := t.Field(i).Tag.Lookup("filter")
+    if !ok || tag == "-" {
+      continue
+    }
+    column, name, _ := strings.Cut(tag, ",")
+    if name == "" {
+      name = "eq"
+    }
+    op, ok := filterOps[name]
+    if !ok || column == "" {
+      return nil, fmt.Errorf("goqdsl: bad filter tag %q on %s.%s", tag, t, t.Field(i).Name)
+    }
+
+    f := rv.Field(i)
+    switch f.Kind() {
+    case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+      if f.IsNil() {
+        continue
+      }
+    }
+    f = reflect.Indirect(f)
+
+    if op != "IN" {
+      conds = append(conds, Cond{column: column, op: op, values: []any{f.Interface()}})
+      continue
+    }
+    if f.Kind() != reflect.Slice && f.Kind() != reflect.Array {
+      return nil, fmt.Errorf("goqdsl: filter %s.%s must be a slice for in", t, t.Field(i).Name)
+    }
+    values := make([]any, f.Len())
+    for j := range values {
+      values[j] = f.Index(j).Interface()
+    }
+    conds = append(conds, Cond{column: column, op: op, values: values})
+  }
+  return conds, nil
+}
+
+// FilterBy adds the conditions of the filter struct v, see Filters. If v is
+// not a valid filter q matches nothing, and CheckIdentifiers reports why.
+func (q *Q) FilterBy(v any) *Q {
+  conds, err := Filters(v)
+  if err != nil {
+    conds = []Cond{{op: "OR", err: err}}
+  }
+  return q.Filter(conds...)
+}
+
+// end
@@ -0,0 +1,61 @@
+package goqdsl
+
+import (
+	"strings"
+	"testing"
+)
+
+type fooFilter struct {
+  Name *string `filter:"name,ilike"`
+  MinAge *int `filter:"age,gte"`
+  Kinds []string `filter:"kind,in"`
+  Active bool `filter:"active"`
+  Page int
+}
+
+func TestFilters(t *testing.T) {
+  name, age := "%bar%", 30
+  q := NewQ().Select("uuid").From("foo").FilterBy(&fooFilter{Name: &name, MinAge: &age, Kinds: []string{"a", "b"}, Page: 2})
+
+  sql, args := q.BuildNamed()
+  expected := "SELECT uuid FROM foo WHERE name ILIKE @name AND   age >= @age AND   kind IN (@kind, @kind_2) AND   active = @active "
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+  if len(args) != 5 || args["name"] != "%bar%" || args["age"] != 30 || args["kind_2"] != "b" || args["active"] != false {
+    t.Errorf("unexpected args: %v", args)
+  }
+
+  sql, _ = NewQ().Select("uuid").From("foo").FilterBy(fooFilter{Active: true}).BuildNamed()
+  if sql != "SELECT uuid FROM foo WHERE active = @active " {
+    t.Errorf("expected nil fields to be skipped, got %s", sql)
+  }
+}
+
+func TestFiltersBadTag(t *testing.T) {
+  for _, v := range []any{
+    struct {
+      Name string `filter:"name,sounds_like"`
+    }{},
+    struct {
+      Kind string `filter:"kind,in"`
+    }{Kind: "a"},
+    "name",
+  } {
+    if _, err := Filters(v); err == nil {
+      t.Errorf("%#v: expected an error", v)
+    }
+  }
+
+  q := NewQ().Select("uuid").From("foo").FilterBy(struct {
+    Name string `filter:"name,sounds_like"`
+  }{})
+  if err := CheckIdentifiers(q); err == nil || !strings.Contains(err.Error(), "bad filter tag") {
+    t.Errorf("expected the bad tag reported, got %v", err)
+  }
+  if sql := q.Query(); sql != "SELECT uuid FROM foo WHERE FALSE " {
+    t.Errorf("expected a bad filter to match nothing, got %s", sql)
+  }
+}
+
+// end
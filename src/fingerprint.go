@@ -0,0 +1,63 @@
+package goqdsl
+
+import (
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// Fingerprint normalizes the SQL b builds the way pg_stat_statements shows
+// queries: parameters and literals become $1, $2, ... in order of appearance
+// and whitespace is collapsed. Builders differing only in values share a
+// fingerprint. The hash is FNV-1a of the normalized SQL.
+func Fingerprint(b Builder) (string, uint64) {
+
+  sql, _ := b.BuildNamed()
+
+  var sb strings.Builder
+  params := map[string]int{}
+  n := 0
+  next := func() string {
+    n++
+    return "$" + strconv.Itoa(n)
+  }
+
+  lex(sql, func(kind tokenKind, text string) {
+    switch kind {
+    case tokenString:
+      sb.WriteString(next())
+    case tokenComment:
+      sb.WriteByte(' ')
+    case tokenParam:
+      if _, ok := params[text]; !ok {
+        params[text] = n + 1
+        next()
+      }
+      sb.WriteString("$" + strconv.Itoa(params[text]))
+    case tokenIdent:
+      sb.WriteString(text)
+    default:
+      for i := 0; i < len(text); i++ {
+        c := text[i]
+        prevIdent := i > 0 && (isParamByte(text[i-1]) || text[i-1] == '$')
+        if c >= '0' && c <= '9' && !prevIdent {
+          j := i
+          for j < len(text) && (text[j] >= '0' && text[j] <= '9' || text[j] == '.') {
+            j++
+          }
+          sb.WriteString(next())
+          i = j - 1
+          continue
+        }
+        sb.WriteByte(c)
+      }
+    }
+  })
+
+  normalized := collapse(sb.String())
+  h := fnv.New64a()
+  h.Write([]byte(normalized))
+  return normalized, h.Sum64()
+}
+
+// end
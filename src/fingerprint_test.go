@@ -0,0 +1,31 @@
+package goqdsl
+
+import (
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+  a, ha := Fingerprint(NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "bar", "uuid": "1"}))
+  b, hb := Fingerprint(NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "baz", "uuid": "2"}))
+
+  if a != "SELECT uuid FROM foo WHERE name = $1 AND uuid = $2" {
+    t.Errorf("unexpected fingerprint: %s", a)
+  }
+  if a != b || ha != hb {
+    t.Errorf("expected equal fingerprints, got %s (%x) and %s (%x)", a, ha, b, hb)
+  }
+
+  _, hc := Fingerprint(NewQ().Select("name").From("foo"))
+  if hc == ha {
+    t.Error("expected different queries to hash differently")
+  }
+}
+
+func TestFingerprintLiterals(t *testing.T) {
+  sql, _ := Fingerprint(raw{"SELECT p1, @x FROM foo WHERE a = 'it''s' AND b = 1.5 AND c = @x  LIMIT 10", args{"x": 1}})
+  if sql != "SELECT p1, $1 FROM foo WHERE a = $2 AND b = $3 AND c = $1 LIMIT $4" {
+    t.Errorf("unexpected fingerprint: %s", sql)
+  }
+}
+
+// end
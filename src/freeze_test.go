@@ -0,0 +1,38 @@
+package goqdsl
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestFreeze(t *testing.T) {
+  criteria := map[string]string{"name": "bar"}
+  base := NewQ().Select("uuid").From("foo").Where(criteria).Freeze()
+  criteria["name"] = "changed"
+
+  var wg sync.WaitGroup
+  for i := 0; i < 8; i++ {
+    wg.Add(1)
+    go func() {
+      defer wg.Done()
+      if sql, args := base.BuildNamed(); sql != "SELECT uuid FROM foo WHERE name = @name " || args["name"] != "bar" {
+        t.Errorf("unexpected build: %s %v", sql, args)
+      }
+    }()
+  }
+  wg.Wait()
+
+  derived := base.Clone().And("active", "true")
+  if sql := derived.Query(); sql != "SELECT uuid FROM foo WHERE active = true AND   name = bar " {
+    t.Errorf("unexpected sql: %s", sql)
+  }
+
+  defer func() {
+    if recover() == nil {
+      t.Error("expected changing a frozen Q to panic")
+    }
+  }()
+  base.Where(nil)
+}
+
+// end
@@ -1,16 +1,15 @@
-package main
+package goqdsl
 
 import (
-	"context"
-	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
-)
-
-var (
-  Conn *pgxpool.Pool
 )
 
 type Join struct {
@@ -26,68 +25,518 @@ type Criteria struct {
 
 type Q struct {
   from string
+  alias string
+  into string
   fields []string
+  exprs map[string]error
   joins []Join
-  criteria map[string]any
+  criteria map[string]string
+  nulls []nullCheck
+  conds []Cond
+  groupBy []string
+  order []Order
+  limit int
+  offset int
+  frozen bool
+  built *built
+}
+
+// built memoizes the renderings of a Q until it changes, so a query that is
+// logged and then executed is rendered once.
+type built struct {
+  query, named, positional sync.Once
+  querySQL, namedSQL, positionalSQL string
+  namedArgs map[string]any
+  positionalArgs []any
+}
+
+type nullCheck struct {
+  column string
+  not bool
 }
 
 func NewQ() *Q {
-  return new(Q)
+  return &Q{built: new(built)}
 }
 
 func (q *Q) Select(fields ...string) *Q {
+  q.mutate()
   q.fields = fields
   return q
 }
 
+// SelectExpr adds exprs to the select list, trusted by CheckIdentifiers as
+// far as each Expr is, e.g. SelectExpr(As(JSONAgg("c.name"), "names")).
+func (q *Q) SelectExpr(exprs ...Expr) *Q {
+  q.mutate()
+  for _, e := range exprs {
+    q.fields = append(q.fields, q.trust(e))
+  }
+  return q
+}
+
+// GroupByExpr adds exprs to GROUP BY, see SelectExpr.
+func (q *Q) GroupByExpr(exprs ...Expr) *Q {
+  q.mutate()
+  for _, e := range exprs {
+    q.groupBy = append(q.groupBy, q.trust(e))
+  }
+  return q
+}
+
+// trust records e for checkIdentifiers and returns its SQL.
+func (q *Q) trust(e Expr) string {
+  if q.exprs == nil {
+    q.exprs = map[string]error{}
+  }
+  if err, ok := q.exprs[e.sql]; !ok || err == nil {
+    q.exprs[e.sql] = e.err
+  }
+  return e.sql
+}
+
 func (q *Q) From(t string) *Q {
+  q.mutate()
   q.from = t
   return q
 }
 
+// FromTable selects from t under its alias. From changes the table and
+// keeps the alias.
+func (q *Q) FromTable(t Table) *Q {
+  q.mutate()
+  q.from = t.name
+  q.alias = t.alias
+  return q
+}
+
+func (q *Q) Table() string {
+  return q.from
+}
+
+func (q *Q) Alias() string {
+  return q.alias
+}
+
+// Joins returns a copy of the joins of q.
+func (q *Q) Joins() []Join {
+  return append([]Join(nil), q.joins...)
+}
+
+// Table returns the joined table as written, possibly with an alias.
+func (j Join) Table() string {
+  return j.table
+}
+
+func (q *Q) Into(t string) *Q {
+  q.mutate()
+  q.into = t
+  return q
+}
+
 func (q *Q) InnerJoin(joins []Join) *Q {
+  q.mutate()
   q.joins = joins
   return q
 }
 
+// Join adds joins to those set by InnerJoin.
+func (q *Q) Join(joins ...Join) *Q {
+  q.mutate()
+  q.joins = append(q.joins, joins...)
+  return q
+}
+
+// Where sets the criteria to a copy of criteria, so later changes to the map
+// do not reach q.
 func (q *Q) Where(criteria map[string]string) *Q {
+  q.mutate()
+  q.criteria = maps.Clone(criteria)
+  return q
+}
+
+// And adds one criterion to those set by Where.
+func (q *Q) And(column, value string) *Q {
+  q.mutate()
+  criteria := make(map[string]string, len(q.criteria)+1)
+  for k, v := range q.criteria {
+    criteria[k] = v
+  }
+  criteria[column] = value
   q.criteria = criteria
   return q
 }
 
-// require generics
-func FetchOne[T any](q *Query) (T, error) {
-  var value T
-  rows, _ := Conn.Query(context.Background(), q.Query(), pgx.NamedArgs(q.Criteria)) 
-  value, err = pgx.CollectOneRow[T](rows, pgx.RowToStructByName[T any])
-  return value
+// IsNull adds column IS NULL for each column. Where values are strings and
+// always compare with =, so this is how to match NULL.
+func (q *Q) IsNull(columns ...string) *Q {
+  q.mutate()
+  for _, c := range columns {
+    q.nulls = append(q.nulls, nullCheck{column: c})
+  }
+  return q
+}
+
+// IsNotNull adds column IS NOT NULL for each column.
+func (q *Q) IsNotNull(columns ...string) *Q {
+  q.mutate()
+  for _, c := range columns {
+    q.nulls = append(q.nulls, nullCheck{column: c, not: true})
+  }
+  return q
+}
+
+// Filter adds typed conditions, usually made from generated Columns.
+func (q *Q) Filter(conds ...Cond) *Q {
+  q.mutate()
+  q.conds = append(q.conds, conds...)
+  return q
+}
+
+// Limit sets LIMIT n; zero or less leaves it out.
+func (q *Q) Limit(n int) *Q {
+  q.mutate()
+  q.limit = n
+  return q
+}
+
+// Offset sets OFFSET n; zero or less leaves it out.
+func (q *Q) Offset(n int) *Q {
+  q.mutate()
+  q.offset = n
+  return q
+}
+
+// WhereMap adds column = value for each entry, in column order, binding the
+// values with their type. A nil value, also a nil pointer, adds IS NULL.
+func (q *Q) WhereMap(values map[string]any) *Q {
+  q.mutate()
+  columns := make([]string, 0, len(values))
+  for c := range values {
+    columns = append(columns, c)
+  }
+  sort.Strings(columns)
+  for _, c := range columns {
+    v := values[c]
+    if rv := reflect.ValueOf(v); v == nil || rv.Kind() == reflect.Pointer && rv.IsNil() {
+      q.nulls = append(q.nulls, nullCheck{column: c})
+      continue
+    }
+    q.conds = append(q.conds, Cond{column: c, op: "=", values: []any{v}})
+  }
+  return q
+}
+
+func (q *Q) GroupBy(columns ...string) *Q {
+  q.mutate()
+  q.groupBy = append(q.groupBy, columns...)
+  return q
+}
+
+func (q *Q) OrderBy(orders ...Order) *Q {
+  q.mutate()
+  q.order = append(q.order, orders...)
+  return q
+}
+
+// Freeze makes q read-only, so a base query can be shared between goroutines
+// and built concurrently. Changing a frozen Q panics; Clone it instead.
+func (q *Q) Freeze() *Q {
+  if !q.frozen {
+    *q = *q.Clone()
+    q.frozen = true
+  }
+  return q
+}
+
+// mutate is called by everything changing q, and drops what was built.
+func (q *Q) mutate() {
+  if q.frozen {
+    panic("goqdsl: changing a frozen Q, Clone it first")
+  }
+  if q.built == nil {
+    q.built = new(built)
+    return
+  }
+  *q.built = built{}
+}
+
+// Clone returns a copy that can be changed without affecting q, also when q
+// is frozen.
+func (q *Q) Clone() *Q {
+  c := *q
+  c.frozen = false
+  c.built = new(built)
+  c.fields = append([]string(nil), q.fields...)
+  c.exprs = maps.Clone(q.exprs)
+  c.joins = append([]Join(nil), q.joins...)
+  c.nulls = append([]nullCheck(nil), q.nulls...)
+  c.conds = append([]Cond(nil), q.conds...)
+  c.groupBy = append([]string(nil), q.groupBy...)
+  c.order = append([]Order(nil), q.order...)
+  if q.criteria != nil {
+    c.criteria = make(map[string]string, len(q.criteria))
+    for k, v := range q.criteria {
+      c.criteria[k] = v
+    }
+  }
+  return &c
+}
+
+type Statement interface {
+  Query() string
 }
 
+type Builder interface {
+  Statement
+  BuildNamed() (string, map[string]any)
+  BuildPositional() (string, []any)
+}
+
+// NamedArgs builds b for pgx, whose named arguments use the same @name
+// placeholders as BuildNamed.
+func NamedArgs(b Builder) (string, pgx.NamedArgs) {
+  sql, args := b.BuildNamed()
+  return sql, pgx.NamedArgs(args)
+}
+
+// Query renders the where values as they are and typed conditions as
+// literals.
 func (q *Q) Query() string {
-  
-  var sql string
-  sql = 
-  "SELECT " + strings.Join(q.fields, ", ") + " " +
-  "FROM " + q.from + " "
- 
-  idx := 0
+  if q.built == nil {
+    return q.build(nil)
+  }
+  q.built.query.Do(func() { q.built.querySQL = q.build(nil) })
+  return q.built.querySQL
+}
+
+// BuildNamed renders the where values as @name parameters, named after their
+// column. A parameter whose name is taken, e.g. by foo.uuid and foo_uuid,
+// gets a _2, _3, ... suffix; typed conditions come after the where values.
+func (q *Q) BuildNamed() (string, map[string]any) {
+  if q.built == nil {
+    return q.buildNamed()
+  }
+  q.built.named.Do(func() { q.built.namedSQL, q.built.namedArgs = q.buildNamed() })
+  return q.built.namedSQL, maps.Clone(q.built.namedArgs)
+}
+
+func (q *Q) buildNamed() (string, map[string]any) {
+  args := make(map[string]any, len(q.criteria)+q.condValues())
+  sql := q.build(func(sb *strings.Builder, k string, v any) {
+    name := paramName(k)
+    base := name
+    for i := 2; ; i++ {
+      if _, taken := args[name]; !taken {
+        break
+      }
+      name = base + "_" + strconv.Itoa(i)
+    }
+    args[name] = v
+    sb.WriteByte('@')
+    sb.WriteString(name)
+  })
+  return sql, args
+}
+
+func (q *Q) BuildPositional() (string, []any) {
+  if q.built == nil {
+    return q.buildPositional()
+  }
+  q.built.positional.Do(func() { q.built.positionalSQL, q.built.positionalArgs = q.buildPositional() })
+  return q.built.positionalSQL, slices.Clone(q.built.positionalArgs)
+}
+
+func (q *Q) buildPositional() (string, []any) {
+  args := make([]any, 0, len(q.criteria)+q.condValues())
+  sql := q.build(func(sb *strings.Builder, k string, v any) {
+    args = append(args, v)
+    writePositional(sb, len(args))
+  })
+  return sql, args
+}
+
+// condValues counts the values in the conds of q.
+func (q *Q) condValues() int {
+  n := 0
+  for _, c := range q.conds {
+    n += c.valueCount()
+  }
+  return n
+}
+
+// build renders q, passing every value to value. A nil value renders them
+// inline for Query. It sizes the builder up front and keeps the sort keys of
+// small where maps on the stack, so it allocates little beyond the result.
+func (q *Q) build(value func(sb *strings.Builder, k string, v any)) string {
+
+  var keyBuf [8]string
+  keys := keyBuf[:0]
+  size := len("SELECT INTO FROM  ") + len(q.into) + len(q.from) + len(q.alias) + len("LIMIT  OFFSET  ") + 40
+  for _, j := range q.joins {
+    size += len("INNER JOIN  ON  =  ") + len(j.table) + len(j.left) + len(j.right)
+  }
+  for _, f := range q.fields {
+    size += len(f) + 2
+  }
   for k, v := range q.criteria {
-    if idx == 0 {
-       sql += "WHERE " + k + " = " + v + " "
+    keys = append(keys, k)
+    size += len("WHERE  =  ") + 2*len(k) + len(v)
+  }
+  slices.Sort(keys)
+  for _, n := range q.nulls {
+    size += len("WHERE  IS NOT NULL ") + len(n.column)
+  }
+  for _, c := range q.conds {
+    size += len("WHERE  ") + c.size()
+  }
+  for _, g := range q.groupBy {
+    size += len("GROUP BY  ") + len(g)
+  }
+  for _, o := range q.order {
+    size += len("ORDER BY  DESC") + len(o.column)
+  }
+
+  var sb strings.Builder
+  sb.Grow(size)
+
+  sb.WriteString("SELECT ")
+  for i, f := range q.fields {
+    if i > 0 {
+      sb.WriteString(", ")
+    }
+    sb.WriteString(f)
+  }
+  sb.WriteByte(' ')
+  if q.into != "" {
+    sb.WriteString("INTO ")
+    sb.WriteString(q.into)
+    sb.WriteByte(' ')
+  }
+  sb.WriteString("FROM ")
+  sb.WriteString(q.from)
+  sb.WriteByte(' ')
+  if q.alias != "" {
+    sb.WriteString(q.alias)
+    sb.WriteByte(' ')
+  }
+
+  for _, j := range q.joins {
+    sb.WriteString("INNER JOIN ")
+    sb.WriteString(j.table)
+    sb.WriteString(" ON ")
+    sb.WriteString(j.left)
+    sb.WriteString(" = ")
+    sb.WriteString(j.right)
+    sb.WriteByte(' ')
+  }
+
+  predicates := 0
+  predicate := func() {
+    if predicates == 0 {
+      sb.WriteString("WHERE ")
     } else {
-       sql += "AND   " + k + " = " + v + " "
+      sb.WriteString("AND   ")
     }
-    idx++
+    predicates++
   }
 
-  return sql
+  for _, k := range keys {
+    predicate()
+    sb.WriteString(k)
+    sb.WriteString(" = ")
+    if value == nil {
+      sb.WriteString(q.criteria[k])
+    } else {
+      value(&sb, k, q.criteria[k])
+    }
+    sb.WriteByte(' ')
+  }
+
+  for _, n := range q.nulls {
+    predicate()
+    sb.WriteString(n.column)
+    if n.not {
+      sb.WriteString(" IS NOT NULL ")
+    } else {
+      sb.WriteString(" IS NULL ")
+    }
+  }
+
+  if len(q.conds) > 0 {
+    arg := func(column string, v any) {
+      if value == nil {
+        writeValue(&sb, v)
+      } else {
+        value(&sb, column, v)
+      }
+    }
+    for _, c := range q.conds {
+      predicate()
+      c.write(&sb, arg)
+      sb.WriteByte(' ')
+    }
+  }
+
+  for i, g := range q.groupBy {
+    if i == 0 {
+      sb.WriteString("GROUP BY ")
+    } else {
+      sb.WriteString(", ")
+    }
+    sb.WriteString(g)
+  }
+  if len(q.groupBy) > 0 {
+    sb.WriteByte(' ')
+  }
+
+  for i, o := range q.order {
+    if i == 0 {
+      sb.WriteString("ORDER BY ")
+    } else {
+      sb.WriteString(", ")
+    }
+    o.write(&sb)
+  }
+  if len(q.order) > 0 {
+    sb.WriteByte(' ')
+  }
+
+  var buf [20]byte
+  if q.limit > 0 {
+    sb.WriteString("LIMIT ")
+    sb.Write(strconv.AppendInt(buf[:0], int64(q.limit), 10))
+    sb.WriteByte(' ')
+  }
+  if q.offset > 0 {
+    sb.WriteString("OFFSET ")
+    sb.Write(strconv.AppendInt(buf[:0], int64(q.offset), 10))
+    sb.WriteByte(' ')
+  }
+
+  return sb.String()
 }
 
-func main() {
-  NewQ().
-  q := NewQ().Select("uuid", "name").From("alerts").Where(map[string]string{"uuid": "d3b2aa81-348d-4727-af3f-81eaa9433962"})
-  sql := q.Query()
-  fmt.Printf("sql: \n%s\n", sql)
+// writePositional writes $n without allocating.
+func writePositional(sb *strings.Builder, n int) {
+  var buf [20]byte
+  sb.WriteByte('$')
+  sb.Write(strconv.AppendInt(buf[:0], int64(n), 10))
+}
+
+// paramName maps column to a parameter name of ASCII letters, digits and
+// underscores, starting with a letter or underscore, as lex and pgx take them.
+func paramName(column string) string {
+  name := strings.Map(func(r rune) rune {
+    if r < 0x80 && isParamByte(byte(r)) {
+      return r
+    }
+    return '_'
+  }, column)
+  if name == "" || !isParamStart(name[0]) {
+    return "_" + name
+  }
+  return name
 }
 
 // end
@@ -11,3 +11,188 @@ func TestQueryBuild(t *testing.T) {
 
   fmt.Printf("sql: \n%s", sql)
 }
+
+var (
+  _ Builder = (*Q)(nil)
+  _ Builder = (*CreateTableQ)(nil)
+  _ Builder = (*CreateTableAsQ)(nil)
+  _ Builder = (*MaterializedViewQ)(nil)
+  _ Builder = (*RefreshQ)(nil)
+  _ Builder = (*TruncateQ)(nil)
+  _ Builder = (*PartitionQ)(nil)
+  _ Builder = (*DetachPartitionQ)(nil)
+  _ Builder = (*DropTableQ)(nil)
+  _ Builder = (*Bound)(nil)
+  _ Builder = (*GrantQ)(nil)
+)
+
+func TestBuildNamed(t *testing.T) {
+  q := NewQ().Select("uuid", "name").From("foo").Where(map[string]string{"name": "bar", "foo.uuid": "d3b2aa81"})
+
+  sql, args := q.BuildNamed()
+  expected := "SELECT uuid, name FROM foo WHERE foo.uuid = @foo_uuid AND   name = @name "
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+  if len(args) != 2 || args["foo_uuid"] != "d3b2aa81" || args["name"] != "bar" {
+    t.Errorf("unexpected args: %v", args)
+  }
+}
+
+func TestBuildPositional(t *testing.T) {
+  q := NewQ().Select("uuid", "name").From("foo").Where(map[string]string{"name": "bar", "foo.uuid": "d3b2aa81"})
+
+  sql, args := q.BuildPositional()
+  expected := "SELECT uuid, name FROM foo WHERE foo.uuid = $1 AND   name = $2 "
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+  if len(args) != 2 || args[0] != "d3b2aa81" || args[1] != "bar" {
+    t.Errorf("unexpected args: %v", args)
+  }
+}
+
+func TestNamedArgs(t *testing.T) {
+  sql, args := NamedArgs(NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "bar"}))
+  if sql != "SELECT uuid FROM foo WHERE name = @name " {
+    t.Errorf("unexpected sql: %s", sql)
+  }
+  if args["name"] != "bar" {
+    t.Errorf("unexpected args: %v", args)
+  }
+
+  if _, args := NamedArgs(RefreshMaterializedView("foo_names")); len(args) != 0 {
+    t.Errorf("expected no args, got %v", args)
+  }
+}
+
+func TestCloneAnd(t *testing.T) {
+  criteria := map[string]string{"name": "bar"}
+  q := NewQ().Select("uuid").From("foo").Where(criteria)
+  c := q.Clone().From("app.foo").And("active", "true")
+
+  if sql := c.Query(); sql != "SELECT uuid FROM app.foo WHERE active = true AND   name = bar " {
+    t.Errorf("unexpected sql: %s", sql)
+  }
+  if sql := q.Query(); sql != "SELECT uuid FROM foo WHERE name = bar " {
+    t.Errorf("clone changed the original: %s", sql)
+  }
+  if len(criteria) != 1 {
+    t.Errorf("And changed the caller's map: %v", criteria)
+  }
+}
+
+func TestIsNull(t *testing.T) {
+  q := NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "bar"}).IsNull("deleted_at").IsNotNull("verified_at")
+
+  sql, args := q.BuildNamed()
+  expected := "SELECT uuid FROM foo WHERE name = @name AND   deleted_at IS NULL AND   verified_at IS NOT NULL "
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+  if len(args) != 1 {
+    t.Errorf("expected no args for null checks, got %v", args)
+  }
+
+  if sql := NewQ().Select("uuid").From("foo").IsNull("deleted_at").Query(); sql != "SELECT uuid FROM foo WHERE deleted_at IS NULL " {
+    t.Errorf("unexpected sql: %s", sql)
+  }
+}
+
+func TestWhereMap(t *testing.T) {
+  var parent *string
+  q := NewQ().Select("uuid").From("foo").WhereMap(map[string]any{"name": "bar", "age": 42, "deleted_at": nil, "parent_uuid": parent})
+
+  sql, args := q.BuildNamed()
+  expected := "SELECT uuid FROM foo WHERE deleted_at IS NULL AND   parent_uuid IS NULL AND   age = @age AND   name = @name "
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+  if len(args) != 2 || args["age"] != 42 || args["name"] != "bar" {
+    t.Errorf("unexpected args: %v", args)
+  }
+}
+
+func TestBuildCache(t *testing.T) {
+  q := NewQ().Select("uuid", "name").From("foo").Where(map[string]string{"name": "bar"})
+
+  sql, args := q.BuildNamed()
+  args["name"] = "changed"
+  if again, args := q.BuildNamed(); again != sql || args["name"] != "bar" {
+    t.Errorf("expected the cached build, got %s %v", again, args)
+  }
+  q.Query()
+  if n := testing.AllocsPerRun(10, func() { q.Query() }); n != 0 {
+    t.Errorf("expected no allocations for a cached Query, got %v", n)
+  }
+
+  q.And("active", "true")
+  if sql := q.Query(); sql != "SELECT uuid, name FROM foo WHERE active = true AND   name = bar " {
+    t.Errorf("expected the change to drop the cache, got %s", sql)
+  }
+  if sql, args := q.BuildPositional(); sql != "SELECT uuid, name FROM foo WHERE active = $1 AND   name = $2 " || len(args) != 2 {
+    t.Errorf("unexpected positional build: %s %v", sql, args)
+  }
+  if n := testing.AllocsPerRun(10, func() { q.Limit(10) }); n != 0 {
+    t.Errorf("expected changes to reset the cache in place, got %v allocations", n)
+  }
+}
+
+func TestWhereCopies(t *testing.T) {
+  criteria := map[string]string{"name": "bar"}
+  q := NewQ().Select("uuid").From("foo").Where(criteria)
+  q.Query()
+  criteria["name"] = "changed"
+  criteria["active"] = "true"
+  if sql := q.Query(); sql != "SELECT uuid FROM foo WHERE name = bar " {
+    t.Errorf("expected Where to copy the map, got %s", sql)
+  }
+  if sql := q.Clone().Query(); sql != "SELECT uuid FROM foo WHERE name = bar " {
+    t.Errorf("expected Where to copy the map, got %s", sql)
+  }
+}
+
+func TestBuildNamedCollisions(t *testing.T) {
+  q := NewQ().Select("uuid").From("foo").Where(map[string]string{"foo.uuid": "a", "foo_uuid": "b"}).Filter(Column[string]("foo.uuid").Eq("c"))
+
+  sql, args := q.BuildNamed()
+  expected := "SELECT uuid FROM foo WHERE foo.uuid = @foo_uuid AND   foo_uuid = @foo_uuid_2 AND   foo.uuid = @foo_uuid_3 "
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+  if args["foo_uuid"] != "a" || args["foo_uuid_2"] != "b" || args["foo_uuid_3"] != "c" {
+    t.Errorf("unexpected args: %v", args)
+  }
+}
+
+func TestParamNameASCII(t *testing.T) {
+  for column, expected := range map[string]string{"größe": "gr__e", "foo.uuid": "foo_uuid", "1st": "_1st", `"Order Lines"`: "_Order_Lines_"} {
+    if name := paramName(column); name != expected {
+      t.Errorf("%s: expected %s, got %s", column, expected, name)
+    }
+  }
+  q := NewQ().Select("uuid").From("foo").Where(map[string]string{"größe": "42"})
+  if sql := ToSQL(q); sql != "SELECT uuid FROM foo WHERE größe = '42' " {
+    t.Errorf("expected the parameter bound, got %s", sql)
+  }
+}
+
+// BuildNamed and BuildPositional must bind the same values in the same
+// places, also when column names map to the same parameter name.
+func TestBuildNamedMatchesPositional(t *testing.T) {
+  for _, q := range []*Q{
+    NewQ().Select("uuid").From("foo").Where(map[string]string{"foo.uuid": "a", "foo_uuid": "b", "foo-uuid": "c"}),
+    NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "a"}).Filter(Column[string]("name").In("b", "c"), Or(Column[string]("name").Eq("d"))),
+  } {
+    sql, named := q.BuildNamed()
+    var bound []any
+    lex(sql, func(kind tokenKind, text string) {
+      if kind == tokenParam {
+        bound = append(bound, named[text])
+      }
+    })
+    if _, positional := q.BuildPositional(); fmt.Sprint(bound) != fmt.Sprint(positional) {
+      t.Errorf("%s: named binds %v, positional %v", sql, bound, positional)
+    }
+  }
+}
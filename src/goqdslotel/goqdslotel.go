@@ -0,0 +1,100 @@
+package goqdslotel
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/raugustinus/goqdsl/src/goqdslpgx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const name = "github.com/raugustinus/goqdsl"
+
+// Middleware starts a span per executed builder. db.statement holds the SQL
+// with its @name placeholders, values are never recorded. A nil tracer uses
+// the global provider.
+func Middleware(tracer trace.Tracer) goqdslpgx.Middleware {
+
+  if tracer == nil {
+    tracer = otel.Tracer(name)
+  }
+
+  return func(ctx context.Context, call *goqdslpgx.Call, next goqdslpgx.Next) (goqdslpgx.Result, error) {
+
+    op := operation(call.SQL)
+    attrs := []attribute.KeyValue{
+      attribute.String("db.system", "postgresql"),
+      attribute.String("db.statement", call.SQL),
+      attribute.String("db.operation", op),
+    }
+    if t, ok := call.Builder.(interface{ Table() string }); ok && t.Table() != "" {
+      attrs = append(attrs, attribute.String("db.sql.table", t.Table()))
+    }
+
+    ctx, span := tracer.Start(ctx, op, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+
+    res, err := next(ctx, call)
+    if err != nil {
+      span.RecordError(err)
+      span.SetStatus(codes.Error, err.Error())
+      span.End()
+      return res, err
+    }
+
+    if res.Rows != nil {
+      res.Rows = &rows{Rows: res.Rows, span: span}
+      return res, nil
+    }
+
+    span.SetAttributes(attribute.Int64("db.rows_affected", res.Tag.RowsAffected()))
+    span.End()
+    return res, nil
+  }
+}
+
+func operation(sql string) string {
+  op, _, _ := strings.Cut(strings.TrimSpace(sql), " ")
+  return strings.ToUpper(op)
+}
+
+// rows ends the span once the result set has been read.
+type rows struct {
+  pgx.Rows
+  span trace.Span
+  count int64
+  ended bool
+}
+
+func (r *rows) Next() bool {
+  if r.Rows.Next() {
+    r.count++
+    return true
+  }
+  r.end()
+  return false
+}
+
+func (r *rows) Close() {
+  r.Rows.Close()
+  r.end()
+}
+
+func (r *rows) end() {
+  if r.ended {
+    return
+  }
+  r.ended = true
+
+  r.span.SetAttributes(attribute.Int64("db.rows_returned", r.count))
+  if err := r.Rows.Err(); err != nil {
+    r.span.RecordError(err)
+    r.span.SetStatus(codes.Error, err.Error())
+  }
+  r.span.End()
+}
+
+// end
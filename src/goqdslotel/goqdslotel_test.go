@@ -0,0 +1,56 @@
+package goqdslotel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	goqdsl "github.com/raugustinus/goqdsl/src"
+	"github.com/raugustinus/goqdsl/src/goqdslpgx"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type querier struct {
+  pgx.Tx
+}
+
+func (q querier) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+  return pgconn.NewCommandTag("SELECT 3"), nil
+}
+
+func TestMiddleware(t *testing.T) {
+  exporter := tracetest.NewInMemoryExporter()
+  provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+  db := goqdslpgx.Wrap(querier{}).Use(Middleware(provider.Tracer("test")))
+  _, err := db.Exec(context.Background(), goqdsl.NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "secret"}))
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  spans := exporter.GetSpans()
+  if len(spans) != 1 {
+    t.Fatalf("expected 1 span, got %d", len(spans))
+  }
+
+  attrs := map[attribute.Key]attribute.Value{}
+  for _, kv := range spans[0].Attributes {
+    attrs[kv.Key] = kv.Value
+  }
+
+  if spans[0].Name != "SELECT" {
+    t.Errorf("unexpected span name: %s", spans[0].Name)
+  }
+  if s := attrs["db.statement"].AsString(); s != "SELECT uuid FROM foo WHERE name = @name " {
+    t.Errorf("unexpected db.statement: %s", s)
+  }
+  if s := attrs["db.sql.table"].AsString(); s != "foo" {
+    t.Errorf("unexpected db.sql.table: %s", s)
+  }
+  if n := attrs["db.rows_affected"].AsInt64(); n != 3 {
+    t.Errorf("unexpected db.rows_affected: %d", n)
+  }
+}
@@ -0,0 +1,38 @@
+package goqdslpgx
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+// RowsAffectedError is returned by ExecAffected for statements whose command
+// tag carries no row count, such as DDL.
+type RowsAffectedError struct {
+  CommandTag string
+}
+
+func (e *RowsAffectedError) Error() string {
+  return "goqdslpgx: " + e.CommandTag + " does not report rows affected"
+}
+
+func (db *PgxDB) ExecAffected(ctx context.Context, b goqdsl.Builder, opts ...ExecOption) (int64, error) {
+
+  tag, err := db.Exec(ctx, b, opts...)
+  if err != nil {
+    return 0, err
+  }
+
+  fields := strings.Fields(tag.String())
+  if len(fields) < 2 {
+    return 0, &RowsAffectedError{CommandTag: tag.String()}
+  }
+  if _, err := strconv.ParseInt(fields[len(fields)-1], 10, 64); err != nil {
+    return 0, &RowsAffectedError{CommandTag: tag.String()}
+  }
+  return tag.RowsAffected(), nil
+}
+
+// end
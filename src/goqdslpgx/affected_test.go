@@ -0,0 +1,29 @@
+package goqdslpgx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+func TestExecAffected(t *testing.T) {
+  q := goqdsl.NewQ().Select("uuid").From("foo")
+
+  n, err := Wrap(&recorder{tag: "INSERT 0 4"}).ExecAffected(context.Background(), q)
+  if err != nil || n != 4 {
+    t.Errorf("expected 4, got %d (%v)", n, err)
+  }
+
+  n, err = Wrap(&recorder{tag: "DELETE 0"}).ExecAffected(context.Background(), q)
+  if err != nil || n != 0 {
+    t.Errorf("expected 0, got %d (%v)", n, err)
+  }
+
+  _, err = Wrap(&recorder{tag: "CREATE TABLE"}).ExecAffected(context.Background(), goqdsl.CreateTable("foo").Column("uuid", "uuid"))
+  var affectedErr *RowsAffectedError
+  if !errors.As(err, &affectedErr) || affectedErr.CommandTag != "CREATE TABLE" {
+    t.Errorf("expected RowsAffectedError, got %v", err)
+  }
+}
@@ -0,0 +1,58 @@
+package goqdslpgx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+// Batch sends all builders in one round trip and returns a command tag per
+// statement. Outside a transaction the batch runs in an implicit one, so a
+// failing statement undoes the ones before it. Middleware is not applied,
+// DryRun is.
+func (db *PgxDB) Batch(ctx context.Context, builders ...goqdsl.Builder) ([]pgconn.CommandTag, error) {
+
+  batch := &pgx.Batch{}
+  for _, b := range builders {
+    b, err := db.rewrite(ctx, b)
+    if err != nil {
+      return nil, err
+    }
+    sql, args := goqdsl.NamedArgs(b)
+    if err := checkParams(args); err != nil {
+      return nil, err
+    }
+    batch.Queue(sql, args)
+  }
+
+  if db.dryRun != nil {
+    tags := make([]pgconn.CommandTag, 0, len(batch.QueuedQueries))
+    for _, q := range batch.QueuedQueries {
+      db.dryRun(q.SQL, q.Arguments[0].(pgx.NamedArgs))
+      tags = append(tags, dryRunTag(q.SQL))
+    }
+    return tags, nil
+  }
+
+  var tags []pgconn.CommandTag
+  err := db.retrying(ctx, func() error {
+
+    tags = make([]pgconn.CommandTag, 0, len(builders))
+    results := db.q.SendBatch(ctx, batch)
+    for i := range builders {
+      tag, err := results.Exec()
+      if err != nil {
+        results.Close()
+        return fmt.Errorf("batch statement %d: %w", i, err)
+      }
+      tags = append(tags, tag)
+    }
+    return results.Close()
+  })
+  return tags, err
+}
+
+// end
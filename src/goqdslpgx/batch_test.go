@@ -0,0 +1,26 @@
+package goqdslpgx
+
+import (
+	"context"
+	"testing"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+func TestBatch(t *testing.T) {
+  rec := &recorder{}
+
+  tags, err := Wrap(rec).Batch(context.Background(),
+    goqdsl.RefreshMaterializedView("foo_names"),
+    goqdsl.Grant("SELECT").OnTable("foo_names").To("svc_foo"))
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  if len(tags) != 2 || tags[1].RowsAffected() != 1 {
+    t.Errorf("unexpected tags: %v", tags)
+  }
+  if len(rec.log) != 2 || rec.log[0] != "REFRESH MATERIALIZED VIEW foo_names" || rec.log[1] != "GRANT SELECT ON TABLE foo_names TO svc_foo" {
+    t.Errorf("unexpected statements: %v", rec.log)
+  }
+}
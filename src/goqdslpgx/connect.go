@@ -0,0 +1,90 @@
+package goqdslpgx
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Option func(*pgxpool.Config)
+
+func Connect(ctx context.Context, dsn string, opts ...Option) (*PgxDB, error) {
+
+  cfg, err := pgxpool.ParseConfig(dsn)
+  if err != nil {
+    return nil, err
+  }
+  for _, opt := range opts {
+    opt(cfg)
+  }
+
+  pool, err := pgxpool.NewWithConfig(ctx, cfg)
+  if err != nil {
+    return nil, err
+  }
+  return New(pool), nil
+}
+
+// StatementCache prepares statements server side, keyed by the generated SQL,
+// and keeps up to capacity of them per connection, evicting the least recently
+// used. A capacity of 0 disables preparing altogether.
+func StatementCache(capacity int) Option {
+  return func(cfg *pgxpool.Config) {
+    cfg.ConnConfig.StatementCacheCapacity = capacity
+    if capacity > 0 {
+      cfg.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeCacheStatement
+    } else {
+      cfg.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeExec
+    }
+  }
+}
+
+// OnConnect runs fn once on every new connection, before the pool hands it
+// out. An error closes the connection and fails the acquire.
+func OnConnect(fn func(ctx context.Context, conn *pgx.Conn) error) Option {
+  return func(cfg *pgxpool.Config) {
+    prev := cfg.AfterConnect
+    cfg.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+      if prev != nil {
+        if err := prev(ctx, conn); err != nil {
+          return err
+        }
+      }
+      return fn(ctx, conn)
+    }
+  }
+}
+
+// OnAcquire runs fn on every checkout of a connection from the pool. The
+// pool cannot return an error from here, so an error is logged to
+// slog.Default and discards the connection, and the pool tries another one;
+// a hook that always fails blocks until the context is done. Settings that
+// last for the session are cheaper with OnConnect or Set.
+func OnAcquire(fn func(ctx context.Context, conn *pgx.Conn) error) Option {
+  return func(cfg *pgxpool.Config) {
+    prev := cfg.BeforeAcquire
+    cfg.BeforeAcquire = func(ctx context.Context, conn *pgx.Conn) bool {
+      if prev != nil && !prev(ctx, conn) {
+        return false
+      }
+      if err := fn(ctx, conn); err != nil {
+        slog.WarnContext(ctx, "goqdslpgx: acquire hook failed, discarding the connection", "err", err)
+        return false
+      }
+      return true
+    }
+  }
+}
+
+// Set sets a session setting such as search_path, role or TimeZone on every
+// new connection.
+func Set(name, value string) Option {
+  return OnConnect(func(ctx context.Context, conn *pgx.Conn) error {
+    _, err := conn.Exec(ctx, "SELECT set_config($1, $2, false)", name, value)
+    return err
+  })
+}
+
+// end
@@ -0,0 +1,69 @@
+package goqdslpgx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestStatementCache(t *testing.T) {
+  cfg, err := pgxpool.ParseConfig("postgres://localhost/foo")
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  StatementCache(64)(cfg)
+  if cfg.ConnConfig.StatementCacheCapacity != 64 || cfg.ConnConfig.DefaultQueryExecMode != pgx.QueryExecModeCacheStatement {
+    t.Errorf("expected cached statements, got capacity %d mode %v", cfg.ConnConfig.StatementCacheCapacity, cfg.ConnConfig.DefaultQueryExecMode)
+  }
+
+  StatementCache(0)(cfg)
+  if cfg.ConnConfig.DefaultQueryExecMode != pgx.QueryExecModeExec {
+    t.Errorf("expected exec mode, got %v", cfg.ConnConfig.DefaultQueryExecMode)
+  }
+}
+
+func TestConnectHooks(t *testing.T) {
+  cfg, err := pgxpool.ParseConfig("postgres://localhost/foo")
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  var calls []string
+  hook := func(name string, err error) func(context.Context, *pgx.Conn) error {
+    return func(context.Context, *pgx.Conn) error {
+      calls = append(calls, name)
+      return err
+    }
+  }
+
+  OnConnect(hook("connect 1", nil))(cfg)
+  OnConnect(hook("connect 2", nil))(cfg)
+  OnAcquire(hook("acquire 1", nil))(cfg)
+  OnAcquire(hook("acquire 2", errors.New("boom")))(cfg)
+
+  if err := cfg.AfterConnect(context.Background(), nil); err != nil {
+    t.Fatal(err)
+  }
+  var buf bytes.Buffer
+  defer slog.SetDefault(slog.Default())
+  slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+  if cfg.BeforeAcquire(context.Background(), nil) {
+    t.Error("expected a failing acquire hook to discard the connection")
+  }
+  if !strings.Contains(buf.String(), "err=boom") {
+    t.Errorf("expected the hook error logged, got %q", buf.String())
+  }
+
+  expected := []string{"connect 1", "connect 2", "acquire 1", "acquire 2"}
+  if !reflect.DeepEqual(calls, expected) {
+    t.Errorf("expected %v, got %v", expected, calls)
+  }
+}
@@ -0,0 +1,37 @@
+package goqdslpgx
+
+import (
+	"reflect"
+	"sync"
+)
+
+var (
+  convertersMu sync.RWMutex
+  converters = map[reflect.Type]func(src any) (any, error){}
+)
+
+// RegisterConverter makes the struct fetch helpers fill fields of type T by
+// scanning the column as the driver decodes it (usually a string or []byte)
+// and passing that to fn, for domain types that do not implement sql.Scanner.
+// NULL columns are not passed to fn and leave the field alone.
+func RegisterConverter[T any](fn func(src any) (T, error)) {
+  convertersMu.Lock()
+  defer convertersMu.Unlock()
+  converters[reflect.TypeOf((*T)(nil)).Elem()] = func(src any) (any, error) {
+    return fn(src)
+  }
+}
+
+func converter(t reflect.Type) func(any) (any, error) {
+  convertersMu.RLock()
+  defer convertersMu.RUnlock()
+  return converters[t]
+}
+
+func hasConverters() bool {
+  convertersMu.RLock()
+  defer convertersMu.RUnlock()
+  return len(converters) > 0
+}
+
+// end
@@ -0,0 +1,39 @@
+package goqdslpgx
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+type status struct {
+  code string
+}
+
+func TestRegisterConverter(t *testing.T) {
+  RegisterConverter(func(src any) (status, error) {
+    return status{code: strings.ToUpper(src.(string))}, nil
+  })
+  defer func() {
+    convertersMu.Lock()
+    converters = map[reflect.Type]func(any) (any, error){}
+    convertersMu.Unlock()
+  }()
+
+  type fooStatus struct {
+    Uuid string `db:"uuid"`
+    Status status `db:"status"`
+  }
+
+  db := Wrap(&recorder{rows: &fakeRows{columns: []string{"uuid", "status"}, data: [][]any{{"d3b2aa81", "active"}, {"8f1c2e04", nil}}}})
+  foos, err := FetchAll[fooStatus](context.Background(), db, goqdsl.NewQ().Select("uuid", "status").From("foo"))
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(foos) != 2 || foos[0].Status.code != "ACTIVE" || foos[1].Status.code != "" {
+    t.Errorf("unexpected result: %+v", foos)
+  }
+}
@@ -0,0 +1,41 @@
+package goqdslpgx
+
+import (
+	"context"
+	"strings"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+// wrapped renders another builder's SQL inside prefix and suffix.
+type wrapped struct {
+  inner goqdsl.Builder
+  prefix string
+  suffix string
+}
+
+func (w wrapped) Query() string {
+  return w.prefix + strings.TrimSpace(w.inner.Query()) + w.suffix
+}
+
+func (w wrapped) BuildNamed() (string, map[string]any) {
+  sql, args := w.inner.BuildNamed()
+  return w.prefix + strings.TrimSpace(sql) + w.suffix, args
+}
+
+func (w wrapped) BuildPositional() (string, []any) {
+  sql, args := w.inner.BuildPositional()
+  return w.prefix + strings.TrimSpace(sql) + w.suffix, args
+}
+
+// Count returns the number of rows b would return.
+func (db *PgxDB) Count(ctx context.Context, b goqdsl.Builder, opts ...ExecOption) (int64, error) {
+  return FetchScalar[int64](ctx, db, wrapped{b, "SELECT COUNT(*) FROM (", ") AS count"}, opts...)
+}
+
+// Exists reports whether b returns any row.
+func (db *PgxDB) Exists(ctx context.Context, b goqdsl.Builder, opts ...ExecOption) (bool, error) {
+  return FetchScalar[bool](ctx, db, wrapped{b, "SELECT EXISTS (", ")"}, opts...)
+}
+
+// end
@@ -0,0 +1,32 @@
+package goqdslpgx
+
+import (
+	"context"
+	"testing"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+func TestCount(t *testing.T) {
+  rec := &recorder{rows: &fakeRows{columns: []string{"count"}, data: [][]any{{int64(3)}}}}
+
+  n, err := Wrap(rec).Count(context.Background(), goqdsl.NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "bar"}))
+  if err != nil || n != 3 {
+    t.Fatalf("expected 3, got %d (%v)", n, err)
+  }
+  if rec.sql != "SELECT COUNT(*) FROM (SELECT uuid FROM foo WHERE name = @name) AS count" {
+    t.Errorf("unexpected sql: %s", rec.sql)
+  }
+}
+
+func TestExists(t *testing.T) {
+  rec := &recorder{rows: &fakeRows{columns: []string{"exists"}, data: [][]any{{true}}}}
+
+  ok, err := Wrap(rec).Exists(context.Background(), goqdsl.NewQ().Select("1").From("foo"))
+  if err != nil || !ok {
+    t.Fatalf("expected true, got %v (%v)", ok, err)
+  }
+  if rec.sql != "SELECT EXISTS (SELECT 1 FROM foo)" {
+    t.Errorf("unexpected sql: %s", rec.sql)
+  }
+}
@@ -0,0 +1,65 @@
+package goqdslpgx
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+  // readVerbs start statements that may only read, unless they hold a write.
+  readVerbs = map[string]bool{"SELECT": true, "WITH": true, "VALUES": true, "TABLE": true, "SHOW": true, "EXPLAIN": true}
+  writeWord = regexp.MustCompile(`(?i)\b(?:INSERT|UPDATE|DELETE|MERGE|INTO|TRUNCATE|CREATE|DROP|ALTER|COPY|CALL|LOCK|NOTIFY|GRANT|REVOKE|REFRESH|VACUUM|ANALYZE|ANALYSE)\b`)
+  lockingRead = regexp.MustCompile(`(?i)\bFOR\s+(?:NO\s+KEY\s+)?UPDATE\b`)
+  explainAnalyze = regexp.MustCompile(`(?i)^\s*EXPLAIN\s*(?:\([^)]*\bANALY[SZ]E\b|ANALY[SZ]E\b)`)
+)
+
+// DryRun logs every statement that may write through log instead of executing
+// it: Exec and Batch, but also queries such as UPDATE ... RETURNING, upserts
+// and EXPLAIN ANALYZE. Writes report 0 rows affected and return no rows.
+// Plain selects still run, so reads in a "what would this job do" run see the
+// real data.
+func (db *PgxDB) DryRun(log func(sql string, args map[string]any)) *PgxDB {
+  db.dryRun = log
+  return db
+}
+
+// readOnly reports whether sql is a plain read. Anything it cannot tell is
+// taken as a write.
+func readOnly(sql string) bool {
+  verb, _, _ := strings.Cut(strings.TrimSpace(sql), " ")
+  if !readVerbs[strings.ToUpper(verb)] {
+    return false
+  }
+  if explainAnalyze.MatchString(sql) {
+    return false
+  }
+  if strings.EqualFold(verb, "EXPLAIN") {
+    return true
+  }
+  return !writeWord.MatchString(lockingRead.ReplaceAllString(sql, ""))
+}
+
+func dryRunTag(sql string) pgconn.CommandTag {
+  verb, _, _ := strings.Cut(strings.TrimSpace(sql), " ")
+  return pgconn.NewCommandTag(strings.ToUpper(verb) + " 0")
+}
+
+// noRows are the rows of a query held back by DryRun.
+type noRows struct {
+  tag pgconn.CommandTag
+}
+
+func (r *noRows) Close() {}
+func (r *noRows) Err() error { return nil }
+func (r *noRows) CommandTag() pgconn.CommandTag { return r.tag }
+func (r *noRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *noRows) Next() bool { return false }
+func (r *noRows) Scan(dest ...any) error { return pgx.ErrNoRows }
+func (r *noRows) Values() ([]any, error) { return nil, pgx.ErrNoRows }
+func (r *noRows) RawValues() [][]byte { return nil }
+func (r *noRows) Conn() *pgx.Conn { return nil }
+
+// end
@@ -0,0 +1,84 @@
+package goqdslpgx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+func TestDryRun(t *testing.T) {
+  rec := &recorder{rows: fooRows()}
+  var logged []string
+  db := Wrap(rec).DryRun(func(sql string, args map[string]any) {
+    logged = append(logged, sql)
+  })
+
+  up := goqdsl.Upsert("foo", []string{"uuid", "name"}, "uuid").Row("1", "bar")
+  n, err := db.ExecAffected(context.Background(), up)
+  if err != nil || n != 0 {
+    t.Errorf("expected 0 rows affected, got %d %v", n, err)
+  }
+  if _, err := db.Batch(context.Background(), up, up); err != nil {
+    t.Fatal(err)
+  }
+
+  foos, err := FetchAll[foo](context.Background(), db, goqdsl.NewQ().Select("uuid", "name").From("foo"))
+  if err != nil || len(foos) != 3 {
+    t.Errorf("expected queries to run, got %v %v", foos, err)
+  }
+
+  if len(logged) != 3 {
+    t.Errorf("expected 3 logged statements, got %v", logged)
+  }
+  if len(rec.log) != 1 {
+    t.Errorf("expected only the query to be sent, got %v", rec.log)
+  }
+}
+
+func TestDryRunQueries(t *testing.T) {
+  rec := &recorder{rows: fooRows()}
+  var logged []string
+  db := Wrap(rec).DryRun(func(sql string, args map[string]any) {
+    logged = append(logged, sql)
+  })
+  ctx := context.Background()
+
+  returning := statement("UPDATE foo SET name = 'baz' RETURNING uuid, name")
+  if _, err := FetchOne[foo](ctx, db, returning); !errors.Is(err, pgx.ErrNoRows) {
+    t.Errorf("expected no rows from a held back RETURNING, got %v", err)
+  }
+  n, err := UpsertAll(ctx, db, "foo", []foo{{Uuid: "1", Name: "bar"}, {Uuid: "2", Name: "baz"}}, "uuid")
+  if err != nil || n != 0 {
+    t.Errorf("expected 0 rows upserted, got %d %v", n, err)
+  }
+  if _, err := db.Explain(ctx, goqdsl.NewQ().Select("uuid").From("foo"), goqdsl.ExplainOptions{Analyze: true}); err == nil {
+    t.Error("expected EXPLAIN ANALYZE to be held back")
+  }
+
+  if len(logged) != 3 || len(rec.log) != 0 {
+    t.Errorf("expected 3 logged statements and none sent, got %q %q", logged, rec.log)
+  }
+}
+
+func TestReadOnly(t *testing.T) {
+  for sql, expected := range map[string]bool{
+    "SELECT uuid FROM foo WHERE updated_at > @t": true,
+    "select uuid from foo for update": true,
+    "WITH x AS (SELECT 1) SELECT * FROM x": true,
+    "EXPLAIN (FORMAT JSON) DELETE FROM foo": true,
+    "SELECT uuid INTO foo_copy FROM foo": false,
+    "WITH gone AS (DELETE FROM foo RETURNING uuid) SELECT * FROM gone": false,
+    "UPDATE foo SET name = @name RETURNING uuid": false,
+    "EXPLAIN (ANALYZE, FORMAT JSON) SELECT 1": false,
+    "explain analyze SELECT 1": false,
+  } {
+    if readOnly(sql) != expected {
+      t.Errorf("%s: expected readOnly %v", sql, expected)
+    }
+  }
+}
+
+// end
@@ -0,0 +1,71 @@
+package goqdslpgx
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+  ErrUniqueViolation = errors.New("unique violation")
+  ErrForeignKeyViolation = errors.New("foreign key violation")
+  ErrCheckViolation = errors.New("check violation")
+  ErrNotNullViolation = errors.New("not null violation")
+  ErrSerializationFailure = errors.New("serialization failure")
+  ErrDeadlock = errors.New("deadlock detected")
+)
+
+var sqlStates = map[string]error{
+  "23505": ErrUniqueViolation,
+  "23503": ErrForeignKeyViolation,
+  "23514": ErrCheckViolation,
+  "23502": ErrNotNullViolation,
+  "40001": ErrSerializationFailure,
+  "40P01": ErrDeadlock,
+}
+
+// Error is returned for PostgreSQL errors with one of the SQLSTATEs above.
+// errors.Is matches it against its Kind, errors.As still finds the
+// *pgconn.PgError underneath.
+type Error struct {
+  Kind error
+  Constraint string
+  Table string
+  Column string
+  Err *pgconn.PgError
+}
+
+func (e *Error) Error() string {
+  if e.Constraint != "" {
+    return e.Kind.Error() + " on " + e.Constraint + ": " + e.Err.Message
+  }
+  return e.Kind.Error() + ": " + e.Err.Message
+}
+
+func (e *Error) Is(target error) bool {
+  return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+  return e.Err
+}
+
+func mapError(err error) error {
+
+  var pgErr *pgconn.PgError
+  if err == nil || !errors.As(err, &pgErr) {
+    return err
+  }
+  var mapped *Error
+  if errors.As(err, &mapped) {
+    return err
+  }
+
+  kind, ok := sqlStates[pgErr.Code]
+  if !ok {
+    return err
+  }
+  return &Error{Kind: kind, Constraint: pgErr.ConstraintName, Table: pgErr.TableName, Column: pgErr.ColumnName, Err: pgErr}
+}
+
+// end
@@ -0,0 +1,40 @@
+package goqdslpgx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+func TestErrorMapping(t *testing.T) {
+  failing := func(ctx context.Context, call *Call, next Next) (Result, error) {
+    return Result{}, &pgconn.PgError{Code: "23505", ConstraintName: "foo_name_key", TableName: "foo", Message: "duplicate key value violates unique constraint"}
+  }
+
+  _, err := Wrap(&recorder{}).Use(failing).Exec(context.Background(), goqdsl.NewQ().Select("uuid").From("foo"))
+  if !errors.Is(err, ErrUniqueViolation) || errors.Is(err, ErrCheckViolation) {
+    t.Fatalf("expected unique violation, got %v", err)
+  }
+
+  var e *Error
+  if !errors.As(err, &e) || e.Constraint != "foo_name_key" || e.Table != "foo" {
+    t.Errorf("unexpected error details: %+v", e)
+  }
+  var pgErr *pgconn.PgError
+  if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+    t.Errorf("expected underlying PgError, got %v", err)
+  }
+  if err.Error() != "unique violation on foo_name_key: duplicate key value violates unique constraint" {
+    t.Errorf("unexpected message: %s", err)
+  }
+}
+
+func TestErrorMappingUnknownState(t *testing.T) {
+  pgErr := &pgconn.PgError{Code: "42P01"}
+  if err := mapError(pgErr); err != pgErr {
+    t.Errorf("expected unmapped error, got %v", err)
+  }
+}
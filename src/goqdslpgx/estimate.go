@@ -0,0 +1,55 @@
+package goqdslpgx
+
+import (
+	"context"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+// EstimateCount returns the planner's estimate of the rows in table, from
+// pg_class.reltuples, which is as fresh as the last VACUUM or ANALYZE. A
+// table that was never analyzed is counted exactly.
+func (db *PgxDB) EstimateCount(ctx context.Context, table string, opts ...ExecOption) (int64, error) {
+  n, err := FetchScalar[int64](ctx, db, reltuplesQ(table), opts...)
+  if err != nil || n >= 0 {
+    return n, err
+  }
+  return db.Count(ctx, goqdsl.NewQ().Select("*").From(table), opts...)
+}
+
+// EstimateTotal has FetchPage take the total from the planner's row estimate
+// of the query when that is above rows, skipping the exact count; see
+// Page.Estimated. Other statements ignore it.
+func EstimateTotal(rows int64) ExecOption {
+  return func(o *execOptions) { o.estimateAbove = rows }
+}
+
+// planRows returns the planner's row estimate for b.
+func (db *PgxDB) planRows(ctx context.Context, b goqdsl.Builder, opts []ExecOption) (int64, error) {
+  out, err := FetchScalar[[]byte](ctx, db, wrapped{b, "EXPLAIN (FORMAT JSON) ", ""}, opts...)
+  if err != nil {
+    return 0, err
+  }
+  plan, err := goqdsl.ParsePlan(out)
+  if err != nil {
+    return 0, err
+  }
+  return int64(plan.Plan.PlanRows), nil
+}
+
+// reltuplesQ reads the row estimate of a table.
+type reltuplesQ string
+
+func (t reltuplesQ) BuildNamed() (string, map[string]any) {
+  return "SELECT reltuples::bigint FROM pg_class WHERE oid = to_regclass(@table)", map[string]any{"table": string(t)}
+}
+
+func (t reltuplesQ) BuildPositional() (string, []any) {
+  return "SELECT reltuples::bigint FROM pg_class WHERE oid = to_regclass($1)", []any{string(t)}
+}
+
+func (t reltuplesQ) Query() string {
+  return goqdsl.ToSQL(t)
+}
+
+// end
@@ -0,0 +1,75 @@
+package goqdslpgx
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+func TestEstimateCount(t *testing.T) {
+  rec := &recorder{rows: &fakeRows{columns: []string{"reltuples"}, data: [][]any{{int64(120000)}}}}
+
+  n, err := Wrap(rec).EstimateCount(context.Background(), "foo")
+  if err != nil || n != 120000 {
+    t.Errorf("expected 120000, got %d (%v)", n, err)
+  }
+  if rec.sql != "SELECT reltuples::bigint FROM pg_class WHERE oid = to_regclass(@table)" {
+    t.Errorf("unexpected sql: %s", rec.sql)
+  }
+
+  var log []string
+  answer := func(ctx context.Context, call *Call, next Next) (Result, error) {
+    log = append(log, call.SQL)
+    if len(log) == 1 {
+      return Result{Rows: &fakeRows{columns: []string{"reltuples"}, data: [][]any{{int64(-1)}}}}, nil
+    }
+    return Result{Rows: &fakeRows{columns: []string{"count"}, data: [][]any{{int64(7)}}}}, nil
+  }
+  n, err = Wrap(&recorder{}).Use(answer).EstimateCount(context.Background(), "foo")
+  if err != nil || n != 7 || len(log) != 2 || log[1] != "SELECT COUNT(*) FROM (SELECT * FROM foo) AS count" {
+    t.Errorf("expected an exact count of 7 for an unanalyzed table, got %d (%v) %v", n, err, log)
+  }
+}
+
+func TestFetchPageEstimate(t *testing.T) {
+  var log []string
+  answerWith := func(estimate string) Middleware {
+    log = nil
+    return func(ctx context.Context, call *Call, next Next) (Result, error) {
+      log = append(log, call.SQL)
+      switch {
+      case strings.HasPrefix(call.SQL, "EXPLAIN"):
+        plan := []byte(`[{"Plan": {"Node Type": "Seq Scan", "Plan Rows": ` + estimate + `}}]`)
+        return Result{Rows: &fakeRows{columns: []string{"QUERY PLAN"}, data: [][]any{{plan}}}}, nil
+      case strings.Contains(call.SQL, "goqdsl_total"):
+        return Result{Rows: &fakeRows{columns: []string{"uuid", "name", "goqdsl_total"}, data: [][]any{{"d3b2aa81", "bar", int64(3)}}}}, nil
+      default:
+        return Result{Rows: &fakeRows{columns: []string{"uuid", "name"}, data: [][]any{{"d3b2aa81", "bar"}}}}, nil
+      }
+    }
+  }
+  q := goqdsl.NewQ().Select("uuid", "name").From("foo")
+
+  page, err := FetchPage[foo](context.Background(), Wrap(&recorder{}).Use(answerWith("2000000")), q, 2, 10, EstimateTotal(100000))
+  if err != nil {
+    t.Fatal(err)
+  }
+  if log[0] != "EXPLAIN (FORMAT JSON) SELECT uuid, name FROM foo" || log[1] != "SELECT page.* FROM (SELECT uuid, name FROM foo) AS page LIMIT 10 OFFSET 10" {
+    t.Errorf("unexpected statements: %v", log)
+  }
+  if !page.Estimated || page.Total != 2000000 || page.Pages != 200000 {
+    t.Errorf("unexpected page: %+v", page)
+  }
+
+  page, err = FetchPage[foo](context.Background(), Wrap(&recorder{}).Use(answerWith("2000000")), q, 1, 10, EstimateTotal(5000000))
+  if err != nil {
+    t.Fatal(err)
+  }
+  if page.Estimated || page.Total != 3 || len(log) != 2 {
+    t.Errorf("expected an exact count below the threshold, got %+v %v", page, log)
+  }
+}
+
+// end
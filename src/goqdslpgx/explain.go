@@ -0,0 +1,49 @@
+package goqdslpgx
+
+import (
+	"context"
+	"fmt"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+// Explain runs the statement through EXPLAIN and returns the parsed plan. Only
+// the JSON format can be parsed, it is used when no format is given. The
+// statement goes through the same rewriters, tenancy filter, middleware and
+// options as Query, which matters as Analyze executes it.
+func (db *PgxDB) Explain(ctx context.Context, s goqdsl.Statement, opts goqdsl.ExplainOptions, execOpts ...ExecOption) (*goqdsl.Plan, error) {
+
+  if opts.Format == "" {
+    opts.Format = goqdsl.JSON
+  }
+  if opts.Format != goqdsl.JSON {
+    return nil, fmt.Errorf("explain: cannot parse %s plans, use JSON", opts.Format)
+  }
+
+  b, ok := s.(goqdsl.Builder)
+  if !ok {
+    b = statement(s.Query())
+  }
+  out, err := FetchScalar[[]byte](ctx, db, wrapped{b, goqdsl.ExplainQuery(statement(""), opts), ""}, execOpts...)
+  if err != nil {
+    return nil, err
+  }
+  return goqdsl.ParsePlan(out)
+}
+
+// statement is SQL without parameters.
+type statement string
+
+func (s statement) Query() string {
+  return string(s)
+}
+
+func (s statement) BuildNamed() (string, map[string]any) {
+  return string(s), nil
+}
+
+func (s statement) BuildPositional() (string, []any) {
+  return string(s), nil
+}
+
+// end
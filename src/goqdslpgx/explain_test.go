@@ -0,0 +1,49 @@
+package goqdslpgx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+func TestExplain(t *testing.T) {
+  plan := []byte(`[{"Plan": {"Node Type": "Seq Scan", "Relation Name": "foo"}, "Execution Time": 0.5}]`)
+  rec := &recorder{rows: &fakeRows{columns: []string{"QUERY PLAN"}, data: [][]any{{plan}}}}
+
+  p, err := Wrap(rec).Explain(context.Background(), goqdsl.NewQ().Select("uuid").From("foo"), goqdsl.ExplainOptions{Analyze: true})
+  if err != nil {
+    t.Fatal(err)
+  }
+  if rec.sql != "EXPLAIN (ANALYZE, FORMAT JSON) SELECT uuid FROM foo" {
+    t.Errorf("unexpected sql: %s", rec.sql)
+  }
+  if tables := p.Plan.SeqScans(); len(tables) != 1 || tables[0] != "foo" {
+    t.Errorf("unexpected plan: %+v", p)
+  }
+
+  if _, err := Wrap(rec).Explain(context.Background(), goqdsl.NewQ().From("foo"), goqdsl.ExplainOptions{Format: goqdsl.Text}); err == nil {
+    t.Error("expected an error for text plans")
+  }
+}
+
+func TestExplainTenancy(t *testing.T) {
+  plan := []byte(`[{"Plan": {"Node Type": "Seq Scan", "Relation Name": "foo"}}]`)
+  rec := &recorder{rows: &fakeRows{columns: []string{"QUERY PLAN"}, data: [][]any{{plan}}}}
+  db := Wrap(rec).Tenancy("tenant_id", "foo")
+  q := goqdsl.NewQ().Select("uuid").From("foo")
+
+  if _, err := db.Explain(WithTenant(context.Background(), "acme"), q, goqdsl.ExplainOptions{Analyze: true}); err != nil {
+    t.Fatal(err)
+  }
+  if rec.sql != "EXPLAIN (ANALYZE, FORMAT JSON) SELECT uuid FROM foo WHERE tenant_id = @tenant_id" {
+    t.Errorf("unexpected sql: %s", rec.sql)
+  }
+  rec.log = nil
+  if _, err := db.Explain(context.Background(), q, goqdsl.ExplainOptions{Analyze: true}); !errors.Is(err, ErrNoTenant) || len(rec.log) != 0 {
+    t.Errorf("expected ErrNoTenant and nothing sent, got %v %v", err, rec.log)
+  }
+}
+
+// end
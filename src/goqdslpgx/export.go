@@ -0,0 +1,166 @@
+package goqdslpgx
+
+import (
+	"bufio"
+	"context"
+	"database/sql/driver"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+// ExportCSV streams the rows of b to w as CSV, one row at a time, headed by
+// the column names. NULL is an empty field, times are RFC 3339, bytes are hex
+// as in COPY, uuids, numerics and intervals are in their PostgreSQL text
+// format and JSON values are written as JSON. It returns the number of rows written.
+func ExportCSV(ctx context.Context, db *PgxDB, b goqdsl.Builder, w io.Writer, opts ...ExecOption) (int64, error) {
+
+  cw := csv.NewWriter(w)
+  record := []string(nil)
+  n, err := export(ctx, db, b, opts, func(columns []string) error {
+    record = make([]string, len(columns))
+    return cw.Write(columns)
+  }, func(values []any) error {
+    for i, v := range values {
+      s, err := csvField(v)
+      if err != nil {
+        return err
+      }
+      record[i] = s
+    }
+    return cw.Write(record)
+  })
+  cw.Flush()
+  if err == nil {
+    err = cw.Error()
+  }
+  return n, err
+}
+
+// ExportJSONLines streams the rows of b to w as JSON objects keyed by column
+// name, in column order, one per line. Uuids and intervals are strings and
+// numerics numbers. It returns the number of rows written.
+func ExportJSONLines(ctx context.Context, db *PgxDB, b goqdsl.Builder, w io.Writer, opts ...ExecOption) (int64, error) {
+
+  bw := bufio.NewWriter(w)
+  var keys [][]byte
+  n, err := export(ctx, db, b, opts, func(columns []string) error {
+    for _, c := range columns {
+      key, err := json.Marshal(c)
+      if err != nil {
+        return err
+      }
+      keys = append(keys, key)
+    }
+    return nil
+  }, func(values []any) error {
+    bw.WriteByte('{')
+    for i, v := range values {
+      v, err := jsonValue(v)
+      if err != nil {
+        return err
+      }
+      value, err := json.Marshal(v)
+      if err != nil {
+        return err
+      }
+      if i > 0 {
+        bw.WriteByte(',')
+      }
+      bw.Write(keys[i])
+      bw.WriteByte(':')
+      bw.Write(value)
+    }
+    _, err := bw.WriteString("}\n")
+    return err
+  })
+  if ferr := bw.Flush(); err == nil {
+    err = ferr
+  }
+  return n, err
+}
+
+func export(ctx context.Context, db *PgxDB, b goqdsl.Builder, opts []ExecOption, header func(columns []string) error, row func(values []any) error) (int64, error) {
+
+  rows, err := db.Query(ctx, b, opts...)
+  if err != nil {
+    return 0, err
+  }
+  defer rows.Close()
+
+  columns := make([]string, len(rows.FieldDescriptions()))
+  for i, f := range rows.FieldDescriptions() {
+    columns[i] = f.Name
+  }
+  if err := header(columns); err != nil {
+    return 0, err
+  }
+
+  var n int64
+  for rows.Next() {
+    values, err := rows.Values()
+    if err != nil {
+      return n, err
+    }
+    if err := row(values); err != nil {
+      return n, err
+    }
+    n++
+  }
+  return n, mapError(rows.Err())
+}
+
+func csvField(v any) (string, error) {
+  switch v := v.(type) {
+  case nil:
+    return "", nil
+  case string:
+    return v, nil
+  case []byte:
+    return `\x` + hex.EncodeToString(v), nil
+  case [16]byte:
+    return uuidString(v), nil
+  case time.Time:
+    return v.Format(time.RFC3339Nano), nil
+  case map[string]any, []any:
+    b, err := json.Marshal(v)
+    return string(b), err
+  case driver.Valuer:
+    // pgtype values, such as Numeric and Interval, in their text format
+    value, err := v.Value()
+    if err != nil {
+      return "", err
+    }
+    return csvField(value)
+  case fmt.Stringer:
+    return v.String(), nil
+  }
+  return fmt.Sprint(v), nil
+}
+
+// jsonValue returns v as it is to be marshaled: uuids, which pgx decodes
+// as [16]byte, as strings, and pgtype values without their own JSON form in
+// their text format.
+func jsonValue(v any) (any, error) {
+  switch v := v.(type) {
+  case [16]byte:
+    return uuidString(v), nil
+  case json.Marshaler:
+    return v, nil
+  case driver.Valuer:
+    return v.Value()
+  }
+  return v, nil
+}
+
+func uuidString(u [16]byte) string {
+  s := hex.EncodeToString(u[:])
+  return s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
+
+// end
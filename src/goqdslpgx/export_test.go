@@ -0,0 +1,99 @@
+package goqdslpgx
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+func exportRows() *fakeRows {
+  created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+  return &fakeRows{
+    columns: []string{"uuid", "name", "created", "meta"},
+    data: [][]any{
+      {"d3b2aa81", "bar, \"baz\"", created, map[string]any{"a": 1.0}},
+      {"8f1c2e04", nil, created, []byte{0xca, 0xfe}},
+    },
+  }
+}
+
+func TestExportCSV(t *testing.T) {
+  var sb strings.Builder
+  n, err := ExportCSV(context.Background(), Wrap(&recorder{rows: exportRows()}), goqdsl.NewQ().Select("*").From("foo"), &sb)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  expected := "uuid,name,created,meta\n" +
+    "d3b2aa81,\"bar, \"\"baz\"\"\",2024-03-01T12:00:00Z,\"{\"\"a\"\":1}\"\n" +
+    "8f1c2e04,,2024-03-01T12:00:00Z,\\xcafe\n"
+  if n != 2 || sb.String() != expected {
+    t.Errorf("expected 2 rows:\n%s\ngot %d:\n%s", expected, n, sb.String())
+  }
+}
+
+func TestExportJSONLines(t *testing.T) {
+  var sb strings.Builder
+  n, err := ExportJSONLines(context.Background(), Wrap(&recorder{rows: exportRows()}), goqdsl.NewQ().Select("*").From("foo"), &sb)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  expected := `{"uuid":"d3b2aa81","name":"bar, \"baz\"","created":"2024-03-01T12:00:00Z","meta":{"a":1}}` + "\n" +
+    `{"uuid":"8f1c2e04","name":null,"created":"2024-03-01T12:00:00Z","meta":"yv4="}` + "\n"
+  if n != 2 || sb.String() != expected {
+    t.Errorf("expected 2 rows:\n%s\ngot %d:\n%s", expected, n, sb.String())
+  }
+}
+
+// pgTypeRows holds what pgx decodes uuid, numeric and interval columns into.
+func pgTypeRows(t *testing.T) *fakeRows {
+  var total pgtype.Numeric
+  if err := total.Scan("12.50"); err != nil {
+    t.Fatal(err)
+  }
+  uuid := [16]byte{0xd3, 0xb2, 0xaa, 0x81, 0x34, 0x8d, 0x47, 0x27, 0xaf, 0x3f, 0x81, 0xea, 0xa9, 0x43, 0x39, 0x62}
+  took := pgtype.Interval{Days: 1, Microseconds: int64(2 * time.Hour / time.Microsecond), Valid: true}
+  return &fakeRows{
+    columns: []string{"uuid", "total", "took"},
+    data: [][]any{{uuid, total, took}, {uuid, pgtype.Numeric{}, nil}},
+  }
+}
+
+func TestExportPgTypes(t *testing.T) {
+  q := goqdsl.NewQ().Select("uuid", "total", "took").From("orders")
+
+  var sb strings.Builder
+  if _, err := ExportCSV(context.Background(), Wrap(&recorder{rows: pgTypeRows(t)}), q, &sb); err != nil {
+    t.Fatal(err)
+  }
+  expected := "uuid,total,took\n" +
+    "d3b2aa81-348d-4727-af3f-81eaa9433962,12.50,1 day 02:00:00.000000\n" +
+    "d3b2aa81-348d-4727-af3f-81eaa9433962,,\n"
+  if sb.String() != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sb.String())
+  }
+
+  sb.Reset()
+  if _, err := ExportJSONLines(context.Background(), Wrap(&recorder{rows: pgTypeRows(t)}), q, &sb); err != nil {
+    t.Fatal(err)
+  }
+  expected = `{"uuid":"d3b2aa81-348d-4727-af3f-81eaa9433962","total":12.50,"took":"1 day 02:00:00.000000"}` + "\n" +
+    `{"uuid":"d3b2aa81-348d-4727-af3f-81eaa9433962","total":null,"took":null}` + "\n"
+  if sb.String() != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sb.String())
+  }
+}
+
+func TestExportQueryError(t *testing.T) {
+  var sb strings.Builder
+  if _, err := ExportCSV(context.Background(), Wrap(&recorder{}), goqdsl.NewQ().Select("*").From("foo"), &sb); err == nil || sb.Len() != 0 {
+    t.Errorf("expected an error and no output, got %v: %q", err, sb.String())
+  }
+}
+
+// end
@@ -0,0 +1,44 @@
+//go:build go1.23
+
+package goqdslpgx
+
+import (
+	"context"
+	"iter"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+// FetchSeq scans rows into T one at a time as the loop asks for them. A failed
+// query or scan is yielded as the final error; breaking out of the loop closes
+// the rows. Retries do not apply.
+func FetchSeq[T any](ctx context.Context, db *PgxDB, b goqdsl.Builder, opts ...ExecOption) iter.Seq2[T, error] {
+  return func(yield func(T, error) bool) {
+
+    var zero T
+    res, err := db.run(ctx, OpQuery, b, opts)
+    if err != nil {
+      yield(zero, mapError(err))
+      return
+    }
+    rows := res.Rows
+    defer rows.Close()
+
+    rowToT := rowTo[T](db, false)
+    for rows.Next() {
+      value, err := rowToT(rows)
+      if err != nil {
+        yield(zero, err)
+        return
+      }
+      if !yield(value, nil) {
+        return
+      }
+    }
+    if err := rows.Err(); err != nil {
+      yield(zero, mapError(err))
+    }
+  }
+}
+
+// end
@@ -0,0 +1,33 @@
+//go:build go1.23
+
+package goqdslpgx
+
+import (
+	"context"
+	"testing"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+func TestFetchSeq(t *testing.T) {
+  rows := fooRows()
+  db := Wrap(&recorder{rows: rows})
+
+  var names []string
+  for f, err := range FetchSeq[foo](context.Background(), db, goqdsl.NewQ().Select("uuid", "name").From("foo")) {
+    if err != nil {
+      t.Fatal(err)
+    }
+    names = append(names, f.Name)
+    if len(names) == 2 {
+      break
+    }
+  }
+
+  if len(names) != 2 || names[0] != "bar" || names[1] != "baz" {
+    t.Errorf("unexpected names: %v", names)
+  }
+  if !rows.closed {
+    t.Error("expected rows to be closed after break")
+  }
+}
@@ -0,0 +1,183 @@
+package goqdslpgx
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+// Querier is implemented by *pgxpool.Pool, *pgxpool.Conn, *pgx.Conn and pgx.Tx.
+type Querier interface {
+  Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+  Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+  QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+  Begin(ctx context.Context) (pgx.Tx, error)
+  SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+type PgxDB struct {
+  pool *pgxpool.Pool
+  q Querier
+  middleware []Middleware
+  rewriters []Rewriter
+  tenancy *tenancy
+  retry *RetryPolicy
+  nullZero bool
+  inTx bool
+  monitor *monitor
+  dryRun func(sql string, args map[string]any)
+  strict bool
+}
+
+func New(pool *pgxpool.Pool) *PgxDB {
+  return &PgxDB{pool: pool, q: pool, monitor: &monitor{}}
+}
+
+// Wrap runs builders on any Querier, e.g. a transaction or a single connection.
+func Wrap(q Querier) *PgxDB {
+  return &PgxDB{q: q, monitor: &monitor{}}
+}
+
+// Pool is nil when the PgxDB wraps something other than a pool.
+func (db *PgxDB) Pool() *pgxpool.Pool {
+  return db.pool
+}
+
+func (db *PgxDB) Exec(ctx context.Context, b goqdsl.Builder, opts ...ExecOption) (pgconn.CommandTag, error) {
+  var tag pgconn.CommandTag
+  err := db.retrying(ctx, func() error {
+    res, err := db.run(ctx, OpExec, b, opts)
+    tag = res.Tag
+    return err
+  })
+  return tag, err
+}
+
+func (db *PgxDB) Query(ctx context.Context, b goqdsl.Builder, opts ...ExecOption) (pgx.Rows, error) {
+  var rows pgx.Rows
+  err := db.retrying(ctx, func() error {
+    res, err := db.run(ctx, OpQuery, b, opts)
+    rows = res.Rows
+    return err
+  })
+  return rows, err
+}
+
+// Tx runs fn in a transaction, committing when fn returns nil. With a retry
+// policy the whole transaction is retried, statements inside it never are.
+func (db *PgxDB) Tx(ctx context.Context, fn func(tx *PgxDB) error) error {
+  return db.retrying(ctx, func() error {
+
+    tx, err := db.q.Begin(ctx)
+    if err != nil {
+      return err
+    }
+    defer tx.Rollback(ctx)
+
+    if err := fn(db.withQuerier(tx)); err != nil {
+      return err
+    }
+    return tx.Commit(ctx)
+  })
+}
+
+// Begin starts a transaction, or a savepoint inside one, for callers that
+// need to decide on commit or rollback themselves. The returned PgxDB runs on
+// the transaction with the same configuration as db.
+func (db *PgxDB) Begin(ctx context.Context) (*PgxDB, pgx.Tx, error) {
+  tx, err := db.q.Begin(ctx)
+  if err != nil {
+    return nil, nil, err
+  }
+  return db.withQuerier(tx), tx, nil
+}
+
+func (db *PgxDB) withQuerier(tx pgx.Tx) *PgxDB {
+  return &PgxDB{pool: db.pool, q: tx, middleware: db.middleware, rewriters: db.rewriters, tenancy: db.tenancy, retry: db.retry, nullZero: db.nullZero, inTx: true, monitor: db.monitor, dryRun: db.dryRun, strict: db.strict}
+}
+
+// FetchOne scans the single result row into T by column name, see
+// pgx.RowToStructByName. It returns pgx.ErrNoRows when there is no row.
+func FetchOne[T any](ctx context.Context, db *PgxDB, b goqdsl.Builder, opts ...ExecOption) (T, error) {
+  var value T
+  err := db.retrying(ctx, func() error {
+    rows, err := db.run(ctx, OpQuery, b, opts)
+    if err != nil {
+      return err
+    }
+    value, err = pgx.CollectOneRow(rows.Rows, rowTo[T](db, false))
+    return err
+  })
+  return value, err
+}
+
+// FetchOptional is FetchOne returning found=false instead of pgx.ErrNoRows.
+func FetchOptional[T any](ctx context.Context, db *PgxDB, b goqdsl.Builder, opts ...ExecOption) (T, bool, error) {
+  value, err := FetchOne[T](ctx, db, b, opts...)
+  if errors.Is(err, pgx.ErrNoRows) {
+    return value, false, nil
+  }
+  return value, err == nil, err
+}
+
+func FetchAll[T any](ctx context.Context, db *PgxDB, b goqdsl.Builder, opts ...ExecOption) ([]T, error) {
+  var values []T
+  err := db.retrying(ctx, func() error {
+    rows, err := db.run(ctx, OpQuery, b, opts)
+    if err != nil {
+      return err
+    }
+    values, err = pgx.CollectRows(rows.Rows, rowTo[T](db, false))
+    return err
+  })
+  return values, err
+}
+
+// FetchColumn scans the first column of every row into a slice, other
+// columns are ignored.
+func FetchColumn[T any](ctx context.Context, db *PgxDB, b goqdsl.Builder, opts ...ExecOption) ([]T, error) {
+  var values []T
+  err := db.retrying(ctx, func() error {
+    rows, err := db.run(ctx, OpQuery, b, opts)
+    if err != nil {
+      return err
+    }
+    values, err = pgx.CollectRows(rows.Rows, firstColumn[T])
+    return err
+  })
+  return values, err
+}
+
+// FetchScalar scans the first column of the single result row, e.g. a COUNT
+// or MAX. NULL, as returned by aggregates over no rows, becomes the zero value.
+// A query returning no row at all gives pgx.ErrNoRows.
+func FetchScalar[T any](ctx context.Context, db *PgxDB, b goqdsl.Builder, opts ...ExecOption) (T, error) {
+  var value *T
+  err := db.retrying(ctx, func() error {
+    rows, err := db.run(ctx, OpQuery, b, opts)
+    if err != nil {
+      return err
+    }
+    value, err = pgx.CollectOneRow(rows.Rows, firstColumn[*T])
+    return err
+  })
+  if value == nil {
+    var zero T
+    return zero, err
+  }
+  return *value, err
+}
+
+func firstColumn[T any](row pgx.CollectableRow) (T, error) {
+  var value T
+  dest := make([]any, len(row.FieldDescriptions()))
+  dest[0] = &value
+  err := row.Scan(dest...)
+  return value, err
+}
+
+// end
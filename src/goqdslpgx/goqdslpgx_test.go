@@ -0,0 +1,277 @@
+package goqdslpgx
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+type recorder struct {
+  pgx.Tx
+  sql string
+  args []any
+  log []string
+  rows *fakeRows
+  tag string
+  committed bool
+  rolledBack bool
+}
+
+func (r *recorder) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+  r.sql, r.args = sql, args
+  r.log = append(r.log, sql)
+  if r.tag != "" {
+    return pgconn.NewCommandTag(r.tag), nil
+  }
+  return pgconn.NewCommandTag("UPDATE 1"), nil
+}
+
+func (r *recorder) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+  r.sql, r.args = sql, args
+  r.log = append(r.log, sql)
+  if r.rows != nil {
+    return r.rows, nil
+  }
+  return nil, errors.New("no rows in recorder")
+}
+
+func (r *recorder) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+  rows, err := r.Query(ctx, sql, args...)
+  return fakeRow{rows, err}
+}
+
+type fakeRow struct {
+  rows pgx.Rows
+  err error
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+  if r.err != nil {
+    return r.err
+  }
+  if !r.rows.Next() {
+    return pgx.ErrNoRows
+  }
+  return r.rows.Scan(dest...)
+}
+
+func (r *recorder) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+  return &batchResults{recorder: r, queued: b.QueuedQueries}
+}
+
+type batchResults struct {
+  pgx.BatchResults
+  recorder *recorder
+  queued []*pgx.QueuedQuery
+  pos int
+}
+
+func (b *batchResults) Exec() (pgconn.CommandTag, error) {
+  q := b.queued[b.pos]
+  b.pos++
+  return b.recorder.Exec(context.Background(), q.SQL, q.Arguments...)
+}
+
+func (b *batchResults) Close() error {
+  return nil
+}
+
+func (r *recorder) Begin(ctx context.Context) (pgx.Tx, error) {
+  return r, nil
+}
+
+func (r *recorder) Commit(ctx context.Context) error {
+  r.committed = true
+  return nil
+}
+
+func (r *recorder) Rollback(ctx context.Context) error {
+  if !r.committed {
+    r.rolledBack = true
+  }
+  return nil
+}
+
+type fakeRows struct {
+  pgx.Rows
+  columns []string
+  data [][]any
+  pos int
+  closed bool
+}
+
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription {
+  fields := make([]pgconn.FieldDescription, len(r.columns))
+  for i, c := range r.columns {
+    fields[i].Name = c
+  }
+  return fields
+}
+
+func (r *fakeRows) Next() bool {
+  if r.closed || r.pos >= len(r.data) {
+    r.closed = true
+    return false
+  }
+  r.pos++
+  return true
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+  if len(dest) == 1 {
+    if scanner, ok := dest[0].(pgx.RowScanner); ok {
+      return scanner.ScanRow(r)
+    }
+  }
+  for i, d := range dest {
+    if d == nil {
+      continue
+    }
+    v := r.data[r.pos-1][i]
+    if v == nil {
+      continue
+    }
+    dv, vv := reflect.ValueOf(d).Elem(), reflect.ValueOf(v)
+    if dv.Kind() == reflect.Pointer && dv.Type() != vv.Type() {
+      p := reflect.New(dv.Type().Elem())
+      p.Elem().Set(vv)
+      vv = p
+    }
+    dv.Set(vv)
+  }
+  return nil
+}
+
+func (r *fakeRows) Values() ([]any, error) {
+  return r.data[r.pos-1], nil
+}
+
+func (r *fakeRows) Err() error {
+  return nil
+}
+
+func (r *fakeRows) Close() {
+  r.closed = true
+}
+
+func (r *fakeRows) CommandTag() pgconn.CommandTag {
+  return pgconn.NewCommandTag("SELECT " + strconv.Itoa(len(r.data)))
+}
+
+type foo struct {
+  Uuid string `db:"uuid"`
+  Name string `db:"name"`
+}
+
+func fooRows() *fakeRows {
+  return &fakeRows{
+    columns: []string{"uuid", "name"},
+    data: [][]any{{"d3b2aa81", "bar"}, {"8f1c2e04", "baz"}, {"0b9d4c11", "qux"}},
+  }
+}
+
+func TestFetchAll(t *testing.T) {
+  db := Wrap(&recorder{rows: fooRows()})
+
+  foos, err := FetchAll[foo](context.Background(), db, goqdsl.NewQ().Select("uuid", "name").From("foo"))
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(foos) != 3 || foos[0].Uuid != "d3b2aa81" || foos[2].Name != "qux" {
+    t.Errorf("unexpected result: %+v", foos)
+  }
+}
+
+func TestFetchColumn(t *testing.T) {
+  db := Wrap(&recorder{rows: fooRows()})
+
+  uuids, err := FetchColumn[string](context.Background(), db, goqdsl.NewQ().Select("uuid", "name").From("foo"))
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(uuids) != 3 || uuids[0] != "d3b2aa81" || uuids[1] != "8f1c2e04" || uuids[2] != "0b9d4c11" {
+    t.Errorf("unexpected uuids: %v", uuids)
+  }
+}
+
+func TestFetchScalar(t *testing.T) {
+  q := goqdsl.NewQ().Select("max(name)").From("foo")
+
+  db := Wrap(&recorder{rows: &fakeRows{columns: []string{"max"}, data: [][]any{{"qux"}}}})
+  if max, err := FetchScalar[string](context.Background(), db, q); err != nil || max != "qux" {
+    t.Errorf("expected qux, got %q (%v)", max, err)
+  }
+
+  db = Wrap(&recorder{rows: &fakeRows{columns: []string{"max"}, data: [][]any{{nil}}}})
+  if max, err := FetchScalar[string](context.Background(), db, q); err != nil || max != "" {
+    t.Errorf("expected zero value for NULL, got %q (%v)", max, err)
+  }
+
+  db = Wrap(&recorder{rows: &fakeRows{columns: []string{"max"}}})
+  if _, err := FetchScalar[string](context.Background(), db, q); !errors.Is(err, pgx.ErrNoRows) {
+    t.Errorf("expected pgx.ErrNoRows, got %v", err)
+  }
+}
+
+func TestFetchOptional(t *testing.T) {
+  q := goqdsl.NewQ().Select("uuid", "name").From("foo").Where(map[string]string{"name": "bar"})
+
+  db := Wrap(&recorder{rows: &fakeRows{columns: []string{"uuid", "name"}, data: [][]any{{"d3b2aa81", "bar"}}}})
+  f, found, err := FetchOptional[foo](context.Background(), db, q)
+  if err != nil || !found || f.Uuid != "d3b2aa81" {
+    t.Errorf("expected foo, got %+v found=%v (%v)", f, found, err)
+  }
+
+  db = Wrap(&recorder{rows: &fakeRows{columns: []string{"uuid", "name"}}})
+  f, found, err = FetchOptional[foo](context.Background(), db, q)
+  if err != nil || found || f.Uuid != "" {
+    t.Errorf("expected not found, got %+v found=%v (%v)", f, found, err)
+  }
+
+  _, found, err = FetchOptional[foo](context.Background(), Wrap(&recorder{}), q)
+  if err == nil || found {
+    t.Errorf("expected query error, got found=%v (%v)", found, err)
+  }
+}
+
+func TestExecNamedArgs(t *testing.T) {
+  rec := &recorder{}
+  db := Wrap(rec)
+
+  _, err := db.Exec(context.Background(), goqdsl.NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "bar"}))
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  if rec.sql != "SELECT uuid FROM foo WHERE name = @name " {
+    t.Errorf("unexpected sql: %s", rec.sql)
+  }
+  args, ok := rec.args[0].(pgx.NamedArgs)
+  if len(rec.args) != 1 || !ok || args["name"] != "bar" {
+    t.Errorf("expected pgx.NamedArgs, got %#v", rec.args)
+  }
+}
+
+func TestTx(t *testing.T) {
+  rec := &recorder{}
+  err := Wrap(rec).Tx(context.Background(), func(tx *PgxDB) error {
+    _, err := tx.Exec(context.Background(), goqdsl.RefreshMaterializedView("foo_names"))
+    return err
+  })
+  if err != nil || !rec.committed || rec.rolledBack {
+    t.Errorf("expected commit, got err=%v committed=%v rolledBack=%v", err, rec.committed, rec.rolledBack)
+  }
+
+  rec = &recorder{}
+  failure := errors.New("failure")
+  err = Wrap(rec).Tx(context.Background(), func(tx *PgxDB) error { return failure })
+  if !errors.Is(err, failure) || rec.committed || !rec.rolledBack {
+    t.Errorf("expected rollback, got err=%v committed=%v rolledBack=%v", err, rec.committed, rec.rolledBack)
+  }
+}
@@ -0,0 +1,90 @@
+package goqdslpgx
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+func (db *PgxDB) Notify(ctx context.Context, channel, payload string) error {
+  _, err := db.Exec(ctx, goqdsl.Notify(channel, payload))
+  return err
+}
+
+type Notification struct {
+  Channel string
+  Payload string
+  PID uint32
+}
+
+// Subscription delivers notifications on C until its context is done or
+// Close is called, after which C is closed and Err tells why.
+type Subscription struct {
+  C <-chan Notification
+  cancel context.CancelFunc
+  done chan struct{}
+  mu sync.Mutex
+  err error
+}
+
+// Listen holds a pool connection for as long as the subscription runs.
+func (db *PgxDB) Listen(ctx context.Context, channels ...string) (*Subscription, error) {
+
+  if db.pool == nil {
+    return nil, errors.New("goqdslpgx: Listen needs a PgxDB created from a pool")
+  }
+
+  conn, err := db.pool.Acquire(ctx)
+  if err != nil {
+    return nil, err
+  }
+  for _, channel := range channels {
+    if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+      conn.Release()
+      return nil, err
+    }
+  }
+
+  ctx, cancel := context.WithCancel(ctx)
+  c := make(chan Notification)
+  sub := &Subscription{C: c, cancel: cancel, done: make(chan struct{})}
+
+  go func() {
+    defer close(sub.done)
+    defer close(c)
+    defer conn.Release()
+    defer conn.Exec(context.Background(), "UNLISTEN *")
+
+    for {
+      n, err := conn.Conn().WaitForNotification(ctx)
+      if err != nil {
+        sub.mu.Lock()
+        sub.err = err
+        sub.mu.Unlock()
+        return
+      }
+      select {
+      case c <- Notification{Channel: n.Channel, Payload: n.Payload, PID: n.PID}:
+      case <-ctx.Done():
+      }
+    }
+  }()
+
+  return sub, nil
+}
+
+func (s *Subscription) Close() {
+  s.cancel()
+  <-s.done
+}
+
+func (s *Subscription) Err() error {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+  return s.err
+}
+
+// end
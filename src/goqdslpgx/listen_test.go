@@ -0,0 +1,26 @@
+package goqdslpgx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func TestNotify(t *testing.T) {
+  rec := &recorder{}
+  if err := Wrap(rec).Notify(context.Background(), "cache", "foo"); err != nil {
+    t.Fatal(err)
+  }
+
+  args := rec.args[0].(pgx.NamedArgs)
+  if rec.sql != "SELECT pg_notify(@channel, @payload)" || args["channel"] != "cache" || args["payload"] != "foo" {
+    t.Errorf("unexpected statement: %s %v", rec.sql, args)
+  }
+}
+
+func TestListenWithoutPool(t *testing.T) {
+  if _, err := Wrap(&recorder{}).Listen(context.Background(), "cache"); err == nil {
+    t.Error("expected error without a pool")
+  }
+}
@@ -0,0 +1,108 @@
+package goqdslpgx
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+type QueryStats struct {
+  Name string
+  Op Op
+  Duration time.Duration
+  Rows int64
+  Err error
+}
+
+type queryNameKey struct{}
+
+// WithQueryName labels the statements executed with ctx for the metrics hook.
+func WithQueryName(ctx context.Context, name string) context.Context {
+  return context.WithValue(ctx, queryNameKey{}, name)
+}
+
+var (
+  // inList matches the parameters of an IN list as Fingerprint writes them.
+  inList = regexp.MustCompile(`IN \(\$\d+(?:, \$\d+)*\)`)
+  placeholder = regexp.MustCompile(`\$\d+`)
+)
+
+func queryName(ctx context.Context, call *Call) string {
+  if name, ok := ctx.Value(queryNameKey{}).(string); ok {
+    return name
+  }
+  sql, _ := goqdsl.Fingerprint(statement(call.SQL))
+  sql = inList.ReplaceAllString(sql, "IN (...)")
+
+  // Number what is left again, so the lists do not shift it.
+  numbers := map[string]string{}
+  return placeholder.ReplaceAllStringFunc(sql, func(p string) string {
+    if _, ok := numbers[p]; !ok {
+      numbers[p] = "$" + strconv.Itoa(len(numbers)+1)
+    }
+    return numbers[p]
+  })
+}
+
+// Metrics calls hook once per statement with its duration, rows affected or
+// returned, and error. Queries are measured until their rows are read or
+// closed. Statements without a WithQueryName label are named by their
+// goqdsl.Fingerprint, with IN lists of any length written as IN (...), so
+// calls differing only in values share a name fit for a metrics label.
+func Metrics(hook func(QueryStats)) Middleware {
+  return func(ctx context.Context, call *Call, next Next) (Result, error) {
+
+    stats := QueryStats{Name: queryName(ctx, call), Op: call.Op}
+    start := time.Now()
+
+    res, err := next(ctx, call)
+    if err != nil || res.Rows == nil {
+      stats.Duration = time.Since(start)
+      stats.Rows = res.Tag.RowsAffected()
+      stats.Err = err
+      hook(stats)
+      return res, err
+    }
+
+    res.Rows = &measuredRows{Rows: res.Rows, start: start, stats: stats, hook: hook}
+    return res, nil
+  }
+}
+
+type measuredRows struct {
+  pgx.Rows
+  start time.Time
+  stats QueryStats
+  hook func(QueryStats)
+  done bool
+}
+
+func (r *measuredRows) Next() bool {
+  if r.Rows.Next() {
+    r.stats.Rows++
+    return true
+  }
+  r.report()
+  return false
+}
+
+func (r *measuredRows) Close() {
+  r.Rows.Close()
+  r.report()
+}
+
+func (r *measuredRows) report() {
+  if r.done {
+    return
+  }
+  r.done = true
+  r.stats.Duration = time.Since(r.start)
+  r.stats.Err = r.Rows.Err()
+  r.hook(r.stats)
+}
+
+// end
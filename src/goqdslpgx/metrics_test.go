@@ -0,0 +1,47 @@
+package goqdslpgx
+
+import (
+	"context"
+	"testing"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+func TestMetrics(t *testing.T) {
+  var stats []QueryStats
+  db := Wrap(&recorder{}).Use(Metrics(func(s QueryStats) { stats = append(stats, s) }))
+
+  q := goqdsl.NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "bar"})
+  if _, err := db.Exec(WithQueryName(context.Background(), "foo_by_name"), q); err != nil {
+    t.Fatal(err)
+  }
+  if _, err := db.Query(context.Background(), q); err == nil {
+    t.Fatal("expected recorder query error")
+  }
+
+  if len(stats) != 2 {
+    t.Fatalf("expected 2 stats, got %d", len(stats))
+  }
+  if stats[0].Name != "foo_by_name" || stats[0].Op != OpExec || stats[0].Rows != 1 || stats[0].Err != nil {
+    t.Errorf("unexpected exec stats: %+v", stats[0])
+  }
+  if stats[1].Name != "SELECT uuid FROM foo WHERE name = $1" || stats[1].Op != OpQuery || stats[1].Err == nil {
+    t.Errorf("unexpected query stats: %+v", stats[1])
+  }
+}
+
+func TestMetricsName(t *testing.T) {
+  var names []string
+  db := Wrap(&recorder{}).Use(Metrics(func(s QueryStats) { names = append(names, s.Name) }))
+
+  name := goqdsl.Column[string]("name")
+  for _, q := range []*goqdsl.Q{
+    goqdsl.NewQ().Select("uuid").From("foo").Filter(name.In("a")).Limit(10),
+    goqdsl.NewQ().Select("uuid").From("foo").Filter(name.In("a", "b", "c")).Limit(20),
+  } {
+    db.Exec(context.Background(), q)
+  }
+  if len(names) != 2 || names[0] != names[1] || names[0] != "SELECT uuid FROM foo WHERE name IN (...) LIMIT $1" {
+    t.Errorf("expected one name for both, got %q", names)
+  }
+}
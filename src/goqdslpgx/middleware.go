@@ -0,0 +1,103 @@
+package goqdslpgx
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+type Op string
+
+const (
+  OpExec Op = "exec"
+  OpQuery Op = "query"
+)
+
+// Call describes one statement on its way to the database. Middleware may
+// change SQL and Args before calling next.
+type Call struct {
+  Op Op
+  Builder goqdsl.Builder
+  SQL string
+  Args pgx.NamedArgs
+}
+
+// Result holds Tag for exec calls and Rows for query calls. Rows are read
+// after the middleware chain has returned.
+type Result struct {
+  Tag pgconn.CommandTag
+  Rows pgx.Rows
+}
+
+type Next func(ctx context.Context, call *Call) (Result, error)
+
+type Middleware func(ctx context.Context, call *Call, next Next) (Result, error)
+
+// Use appends middleware, the first one added is the outermost.
+func (db *PgxDB) Use(middleware ...Middleware) *PgxDB {
+  db.middleware = append(db.middleware, middleware...)
+  return db
+}
+
+func (db *PgxDB) run(ctx context.Context, op Op, b goqdsl.Builder, opts []ExecOption) (Result, error) {
+
+  b, err := db.rewrite(ctx, b)
+  if err != nil {
+    return Result{}, err
+  }
+
+  sql, args := goqdsl.NamedArgs(b)
+  if err := checkParams(args); err != nil {
+    return Result{}, err
+  }
+  call := &Call{Op: op, Builder: b, SQL: sql, Args: args}
+  done := db.monitor.track(ctx, call)
+
+  ctx, q, prepared, err := db.prepare(ctx, opts)
+  if err != nil {
+    done(err)
+    return Result{}, err
+  }
+  finish := func(err error) error {
+    err = prepared(err)
+    done(err)
+    return err
+  }
+
+  next := func(ctx context.Context, call *Call) (Result, error) {
+    if db.dryRun != nil && !readOnly(call.SQL) {
+      db.dryRun(call.SQL, call.Args)
+      if call.Op == OpExec {
+        return Result{Tag: dryRunTag(call.SQL)}, nil
+      }
+      return Result{Rows: &noRows{tag: dryRunTag(call.SQL)}}, nil
+    }
+    return send(ctx, q, call)
+  }
+  for i := len(db.middleware) - 1; i >= 0; i-- {
+    m, inner := db.middleware[i], next
+    next = func(ctx context.Context, call *Call) (Result, error) {
+      return m(ctx, call, inner)
+    }
+  }
+
+  res, err := next(ctx, call)
+  if err != nil || res.Rows == nil {
+    return res, finish(err)
+  }
+  res.Rows = &finishingRows{Rows: res.Rows, finish: finish}
+  return res, nil
+}
+
+func send(ctx context.Context, q Querier, call *Call) (Result, error) {
+  if call.Op == OpExec {
+    tag, err := q.Exec(ctx, call.SQL, call.Args)
+    return Result{Tag: tag}, err
+  }
+  rows, err := q.Query(ctx, call.SQL, call.Args)
+  return Result{Rows: rows}, err
+}
+
+// end
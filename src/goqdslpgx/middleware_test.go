@@ -0,0 +1,38 @@
+package goqdslpgx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+func TestMiddlewareOrder(t *testing.T) {
+  rec := &recorder{}
+  var order []string
+
+  trace := func(name string) Middleware {
+    return func(ctx context.Context, call *Call, next Next) (Result, error) {
+      order = append(order, name+" "+string(call.Op))
+      return next(ctx, call)
+    }
+  }
+  tenant := func(ctx context.Context, call *Call, next Next) (Result, error) {
+    call.Args["tenant"] = "acme"
+    return next(ctx, call)
+  }
+
+  db := Wrap(rec).Use(trace("outer"), trace("inner"), tenant)
+  _, err := db.Exec(context.Background(), goqdsl.NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "bar"}))
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  if len(order) != 2 || order[0] != "outer exec" || order[1] != "inner exec" {
+    t.Errorf("unexpected middleware order: %v", order)
+  }
+  if args := rec.args[0].(pgx.NamedArgs); args["tenant"] != "acme" || args["name"] != "bar" {
+    t.Errorf("expected middleware to add tenant arg, got %v", args)
+  }
+}
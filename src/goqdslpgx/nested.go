@@ -0,0 +1,201 @@
+package goqdslpgx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+// RowToNestedStruct is a pgx.RowToFunc that scans columns into nested structs
+// by prefix: with a field `User User db:"u"`, column u_name goes to User's
+// name field. Embedded structs without a db tag are flattened as usual.
+func RowToNestedStruct[T any](row pgx.CollectableRow) (T, error) {
+  return rowToStruct[T](row, true, false)
+}
+
+// rowToStruct maps columns to fields by db tag, or lower cased field name.
+// With nested, struct fields hold prefixed columns; with nullZero, NULL leaves
+// non-pointer fields at their zero value instead of failing the scan.
+func rowToStruct[T any](row pgx.CollectableRow, nested bool, nullZero bool) (T, error) {
+
+  var value T
+  v := reflect.ValueOf(&value).Elem()
+  if v.Kind() != reflect.Struct {
+    return value, fmt.Errorf("goqdslpgx: %T is not a struct", value)
+  }
+
+  fieldIndexes := structFields(v.Type(), nested)
+
+  fields := row.FieldDescriptions()
+  dest := make([]any, len(fields))
+  var nullable, converted [][2]reflect.Value
+  for i, f := range fields {
+    index, ok := fieldIndexes[strings.ToLower(f.Name)]
+    if !ok {
+      return value, fmt.Errorf("goqdslpgx: no field in %T for column %s", value, f.Name)
+    }
+    target := v.FieldByIndex(index)
+    if converter(target.Type()) != nil {
+      holder := reflect.New(reflect.TypeOf((*any)(nil)).Elem())
+      converted = append(converted, [2]reflect.Value{target, holder})
+      dest[i] = holder.Interface()
+      continue
+    }
+    if nullZero && target.Kind() != reflect.Pointer && target.Kind() != reflect.Interface {
+      holder := reflect.New(reflect.PointerTo(target.Type()))
+      nullable = append(nullable, [2]reflect.Value{target, holder})
+      dest[i] = holder.Interface()
+      continue
+    }
+    dest[i] = target.Addr().Interface()
+  }
+
+  if err := row.Scan(dest...); err != nil {
+    return value, err
+  }
+  for _, n := range nullable {
+    if p := n[1].Elem(); !p.IsNil() {
+      n[0].Set(p.Elem())
+    }
+  }
+  for _, c := range converted {
+    src := c[1].Elem().Interface()
+    if src == nil {
+      continue
+    }
+    out, err := converter(c[0].Type())(src)
+    if err != nil {
+      return value, err
+    }
+    c[0].Set(reflect.ValueOf(out))
+  }
+  return value, nil
+}
+
+// NullAsZero makes the struct fetch helpers leave non-pointer fields at
+// their zero value when the column is NULL, instead of failing the scan.
+func (db *PgxDB) NullAsZero() *PgxDB {
+  db.nullZero = true
+  return db
+}
+
+// ColumnScanner is implemented by structs with a generated scanner, see
+// goqdsl gen scanners. The fetch helpers prefer it over reflection.
+type ColumnScanner interface {
+  ScanColumns(row pgx.CollectableRow) error
+}
+
+func rowTo[T any](db *PgxDB, nested bool) pgx.RowToFunc[T] {
+  if _, ok := any(new(T)).(ColumnScanner); ok && !db.nullZero {
+    return func(row pgx.CollectableRow) (T, error) {
+      var value T
+      err := any(&value).(ColumnScanner).ScanColumns(row)
+      return value, err
+    }
+  }
+  if !nested && !db.nullZero && !hasConverters() {
+    return pgx.RowToStructByName[T]
+  }
+  return func(row pgx.CollectableRow) (T, error) {
+    return rowToStruct[T](row, nested, db.nullZero)
+  }
+}
+
+var (
+  timeType = reflect.TypeOf(time.Time{})
+  scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+type fieldsKey struct {
+  t reflect.Type
+  nested bool
+}
+
+// fieldsCache holds the field index by lower cased column name per fieldsKey,
+// so reflection over the struct type happens once instead of once per row.
+var fieldsCache sync.Map
+
+func structFields(t reflect.Type, nested bool) map[string][]int {
+
+  key := fieldsKey{t, nested}
+  if cached, ok := fieldsCache.Load(key); ok {
+    return cached.(map[string][]int)
+  }
+
+  fields := map[string][]int{}
+  collectFields(t, nil, "", nested, fields)
+  cached, _ := fieldsCache.LoadOrStore(key, fields)
+  return cached.(map[string][]int)
+}
+
+func collectFields(t reflect.Type, index []int, prefix string, nested bool, fields map[string][]int) {
+
+  for i := 0; i < t.NumField(); i++ {
+
+    f := t.Field(i)
+    if !f.IsExported() {
+      continue
+    }
+    tag, tagged := f.Tag.Lookup("db")
+    if tag == "-" {
+      continue
+    }
+    // options after the name, as in db:"uuid,pk", are for goqdsl models
+    name, _, _ := strings.Cut(tag, ",")
+    if name == "" {
+      tagged = false
+      name = strings.ToLower(f.Name)
+    }
+
+    fieldIndex := append(append([]int{}, index...), i)
+    if f.Type.Kind() == reflect.Struct && f.Type != timeType && !reflect.PointerTo(f.Type).Implements(scannerType) {
+      if f.Anonymous && !tagged {
+        collectFields(f.Type, fieldIndex, prefix, nested, fields)
+        continue
+      }
+      if nested {
+        // a column matching the field itself is a json/jsonb value for pgx
+        // to unmarshal into it
+        fields[strings.ToLower(prefix+name)] = fieldIndex
+        collectFields(f.Type, fieldIndex, prefix+name+"_", nested, fields)
+        continue
+      }
+    }
+    fields[strings.ToLower(prefix+name)] = fieldIndex
+  }
+}
+
+func FetchOneNested[T any](ctx context.Context, db *PgxDB, b goqdsl.Builder, opts ...ExecOption) (T, error) {
+  var value T
+  err := db.retrying(ctx, func() error {
+    rows, err := db.run(ctx, OpQuery, b, opts)
+    if err != nil {
+      return err
+    }
+    value, err = pgx.CollectOneRow(rows.Rows, rowTo[T](db, true))
+    return err
+  })
+  return value, err
+}
+
+func FetchAllNested[T any](ctx context.Context, db *PgxDB, b goqdsl.Builder, opts ...ExecOption) ([]T, error) {
+  var values []T
+  err := db.retrying(ctx, func() error {
+    rows, err := db.run(ctx, OpQuery, b, opts)
+    if err != nil {
+      return err
+    }
+    values, err = pgx.CollectRows(rows.Rows, rowTo[T](db, true))
+    return err
+  })
+  return values, err
+}
+
+// end
@@ -0,0 +1,38 @@
+package goqdslpgx
+
+import (
+	"testing"
+)
+
+func benchmarkRows(n int) *fakeRows {
+  rows := &fakeRows{columns: []string{"f_uuid", "f_name", "b_uuid", "b_total"}}
+  for i := 0; i < n; i++ {
+    rows.data = append(rows.data, []any{"d3b2aa81", "bar", "5e1f0c2a", int64(i)})
+  }
+  return rows
+}
+
+func BenchmarkRowToNestedStruct(b *testing.B) {
+  rows := benchmarkRows(b.N)
+  b.ReportAllocs()
+  b.ResetTimer()
+  for rows.Next() {
+    if _, err := RowToNestedStruct[fooBar](rows); err != nil {
+      b.Fatal(err)
+    }
+  }
+}
+
+func BenchmarkRowToStructNullAsZero(b *testing.B) {
+  rows := &fakeRows{columns: []string{"uuid", "name"}}
+  for i := 0; i < b.N; i++ {
+    rows.data = append(rows.data, []any{"d3b2aa81", nil})
+  }
+  b.ReportAllocs()
+  b.ResetTimer()
+  for rows.Next() {
+    if _, err := rowToStruct[foo](rows, false, true); err != nil {
+      b.Fatal(err)
+    }
+  }
+}
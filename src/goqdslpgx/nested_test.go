@@ -0,0 +1,140 @@
+package goqdslpgx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+type bar struct {
+  Uuid string `db:"uuid"`
+  Total int64 `db:"total"`
+}
+
+type fooBar struct {
+  Foo foo `db:"f"`
+  Bar bar `db:"b"`
+}
+
+func TestFetchAllNested(t *testing.T) {
+  db := Wrap(&recorder{rows: &fakeRows{
+    columns: []string{"f_uuid", "f_name", "b_uuid", "b_total"},
+    data: [][]any{
+      {"d3b2aa81", "bar", "5e1f0c2a", int64(12)},
+      {"8f1c2e04", "baz", "77a0c9e3", int64(40)},
+    },
+  }})
+
+  rows, err := FetchAllNested[fooBar](context.Background(), db, goqdsl.NewQ().Select("f.uuid AS f_uuid", "f.name AS f_name", "b.uuid AS b_uuid", "b.total AS b_total").From("foo f"))
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  if len(rows) != 2 || rows[0].Foo.Name != "bar" || rows[0].Bar.Total != 12 || rows[1].Foo.Uuid != "8f1c2e04" || rows[1].Bar.Uuid != "77a0c9e3" {
+    t.Errorf("unexpected rows: %+v", rows)
+  }
+}
+
+func TestFetchAllNestedUnknownColumn(t *testing.T) {
+  db := Wrap(&recorder{rows: &fakeRows{columns: []string{"f_uuid", "x_name"}, data: [][]any{{"d3b2aa81", "bar"}}}})
+
+  if _, err := FetchAllNested[fooBar](context.Background(), db, goqdsl.NewQ().Select("*").From("foo")); err == nil {
+    t.Error("expected error for unmapped column")
+  }
+}
+
+func TestNullAsZero(t *testing.T) {
+  rows := func() *fakeRows {
+    return &fakeRows{columns: []string{"uuid", "name"}, data: [][]any{{"d3b2aa81", nil}, {"8f1c2e04", "baz"}}}
+  }
+  q := goqdsl.NewQ().Select("uuid", "name").From("foo")
+
+  foos, err := FetchAll[foo](context.Background(), Wrap(&recorder{rows: rows()}).NullAsZero(), q)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(foos) != 2 || foos[0].Uuid != "d3b2aa81" || foos[0].Name != "" || foos[1].Name != "baz" {
+    t.Errorf("unexpected result: %+v", foos)
+  }
+
+  type fooPtr struct {
+    Uuid string `db:"uuid"`
+    Name *string `db:"name"`
+  }
+  ptrs, err := FetchAll[fooPtr](context.Background(), Wrap(&recorder{rows: rows()}).NullAsZero(), q)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if ptrs[0].Name != nil || ptrs[1].Name == nil || *ptrs[1].Name != "baz" {
+    t.Errorf("unexpected result: %+v", ptrs)
+  }
+}
+
+type pkFoo struct {
+  Uuid string `db:"uuid,pk"`
+  Name string `db:"name"`
+}
+
+func TestPKTag(t *testing.T) {
+  rows := func() *fakeRows {
+    return &fakeRows{columns: []string{"uuid", "name"}, data: [][]any{{"d3b2aa81", nil}}}
+  }
+  q := goqdsl.NewQ().Select("uuid", "name").From("foo")
+
+  foos, err := FetchAll[pkFoo](context.Background(), Wrap(&recorder{rows: rows()}).NullAsZero(), q)
+  if err != nil || len(foos) != 1 || foos[0].Uuid != "d3b2aa81" {
+    t.Errorf("unexpected result: %+v (%v)", foos, err)
+  }
+  nested, err := FetchAllNested[pkFoo](context.Background(), Wrap(&recorder{rows: &fakeRows{columns: []string{"uuid", "name"}, data: [][]any{{"d3b2aa81", "bar"}}}}), q)
+  if err != nil || len(nested) != 1 || nested[0].Uuid != "d3b2aa81" || nested[0].Name != "bar" {
+    t.Errorf("unexpected result: %+v (%v)", nested, err)
+  }
+}
+
+func TestFetchAllNestedJSONField(t *testing.T) {
+  type settings struct {
+    Theme string `json:"theme"`
+  }
+  type fooSettings struct {
+    Foo foo `db:"f"`
+    Settings settings `db:"settings"`
+  }
+
+  db := Wrap(&recorder{rows: &fakeRows{
+    columns: []string{"f_uuid", "f_name", "settings"},
+    data: [][]any{{"d3b2aa81", "bar", settings{Theme: "dark"}}},
+  }})
+
+  rows, err := FetchAllNested[fooSettings](context.Background(), db, goqdsl.NewQ().Select("*").From("foo f"))
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(rows) != 1 || rows[0].Foo.Name != "bar" || rows[0].Settings.Theme != "dark" {
+    t.Errorf("unexpected rows: %+v", rows)
+  }
+}
+
+type scannedFoo struct {
+  Uuid string
+  Name string
+  scanned bool
+}
+
+func (f *scannedFoo) ScanColumns(row pgx.CollectableRow) error {
+  f.scanned = true
+  return row.Scan(&f.Uuid, &f.Name)
+}
+
+func TestColumnScanner(t *testing.T) {
+  db := Wrap(&recorder{rows: fooRows()})
+
+  foos, err := FetchAll[scannedFoo](context.Background(), db, goqdsl.NewQ().Select("uuid", "name").From("foo"))
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(foos) != 3 || !foos[0].scanned || foos[1].Name != "baz" {
+    t.Errorf("unexpected result: %+v", foos)
+  }
+}
@@ -0,0 +1,126 @@
+package goqdslpgx
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type ExecOption func(*execOptions)
+
+type execOptions struct {
+  timeout time.Duration
+  settings [][2]string
+  estimateAbove int64
+}
+
+// WithTimeout cancels the statement's context after d. For queries the
+// deadline covers reading the rows.
+func WithTimeout(d time.Duration) ExecOption {
+  return func(o *execOptions) { o.timeout = d }
+}
+
+// StatementTimeout also has the server abort the statement after d, by setting
+// statement_timeout for it with SET LOCAL. Outside a transaction the statement
+// is run in one of its own.
+func StatementTimeout(d time.Duration) ExecOption {
+  return SetLocal("statement_timeout", strconv.FormatInt(d.Milliseconds(), 10))
+}
+
+// SetLocal sets a run-time parameter for the statement only, as SET LOCAL
+// would, e.g. SetLocal("work_mem", "256MB") or SetLocal("role", "reporting").
+// Outside a transaction the statement is run in one of its own; inside one the
+// setting lasts until the transaction ends.
+func SetLocal(name, value string) ExecOption {
+  return func(o *execOptions) {
+    o.settings = append(o.settings, [2]string{name, value})
+  }
+}
+
+// prepare applies the options, returning the querier to run on and a finish
+// function that must be called with the statement's error once it is done.
+func (db *PgxDB) prepare(ctx context.Context, opts []ExecOption) (context.Context, Querier, func(error) error, error) {
+
+  var o execOptions
+  for _, opt := range opts {
+    opt(&o)
+  }
+
+  cancel := func() {}
+  if o.timeout > 0 {
+    ctx, cancel = context.WithTimeout(ctx, o.timeout)
+  }
+
+  if len(o.settings) == 0 {
+    return ctx, db.q, func(err error) error { cancel(); return err }, nil
+  }
+
+  q := db.q
+  var tx pgx.Tx
+  if !db.inTx {
+    var err error
+    if tx, err = db.q.Begin(ctx); err != nil {
+      cancel()
+      return ctx, nil, nil, err
+    }
+    q = tx
+  }
+
+  finish := func(err error) error {
+    if tx != nil {
+      if err == nil {
+        err = tx.Commit(ctx)
+      } else {
+        tx.Rollback(ctx)
+      }
+    }
+    cancel()
+    return err
+  }
+
+  for _, setting := range o.settings {
+    if _, err := q.Exec(ctx, "SELECT set_config($1, $2, true)", setting[0], setting[1]); err != nil {
+      return ctx, nil, nil, finish(err)
+    }
+  }
+  return ctx, q, finish, nil
+}
+
+// finishingRows calls finish when the rows are exhausted or closed.
+type finishingRows struct {
+  pgx.Rows
+  finish func(error) error
+  done bool
+  err error
+}
+
+func (r *finishingRows) Next() bool {
+  if r.Rows.Next() {
+    return true
+  }
+  r.end()
+  return false
+}
+
+func (r *finishingRows) Err() error {
+  if r.err != nil {
+    return r.err
+  }
+  return r.Rows.Err()
+}
+
+func (r *finishingRows) Close() {
+  r.Rows.Close()
+  r.end()
+}
+
+func (r *finishingRows) end() {
+  if !r.done {
+    r.done = true
+    r.err = r.finish(r.Rows.Err())
+  }
+}
+
+// end
@@ -0,0 +1,62 @@
+package goqdslpgx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+func TestWithTimeout(t *testing.T) {
+  var deadline time.Time
+  var ok bool
+  probe := func(ctx context.Context, call *Call, next Next) (Result, error) {
+    deadline, ok = ctx.Deadline()
+    return next(ctx, call)
+  }
+
+  db := Wrap(&recorder{}).Use(probe)
+  if _, err := db.Exec(context.Background(), goqdsl.RefreshMaterializedView("foo_names"), WithTimeout(time.Minute)); err != nil {
+    t.Fatal(err)
+  }
+  if !ok || time.Until(deadline) > time.Minute {
+    t.Errorf("expected a deadline within a minute, got %v", deadline)
+  }
+}
+
+func TestStatementTimeout(t *testing.T) {
+  rec := &recorder{}
+  db := Wrap(rec)
+
+  if _, err := db.Exec(context.Background(), goqdsl.RefreshMaterializedView("foo_names"), StatementTimeout(1500*time.Millisecond)); err != nil {
+    t.Fatal(err)
+  }
+
+  if len(rec.log) != 2 || rec.log[0] != "SELECT set_config($1, $2, true)" || rec.log[1] != "REFRESH MATERIALIZED VIEW foo_names" {
+    t.Errorf("unexpected statements: %v", rec.log)
+  }
+  if !rec.committed {
+    t.Error("expected the statement's transaction to be committed")
+  }
+}
+
+func TestSetLocal(t *testing.T) {
+  rec := &recorder{}
+  db := Wrap(rec)
+
+  err := db.Tx(context.Background(), func(tx *PgxDB) error {
+    _, err := tx.Exec(context.Background(), goqdsl.RefreshMaterializedView("foo_names"), SetLocal("work_mem", "256MB"), SetLocal("role", "reporting"))
+    return err
+  })
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  if len(rec.log) != 3 || rec.log[2] != "REFRESH MATERIALIZED VIEW foo_names" {
+    t.Errorf("unexpected statements: %v", rec.log)
+  }
+  if len(rec.args) != 1 {
+    t.Errorf("unexpected args: %v", rec.args)
+  }
+}
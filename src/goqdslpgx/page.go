@@ -0,0 +1,116 @@
+package goqdslpgx
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+// Page is one page of results. Number is 1-based. Estimated is set when Total
+// is the planner's estimate, see EstimateTotal.
+type Page[T any] struct {
+  Items []T
+  Total int64
+  Number int
+  Size int
+  Pages int
+  Estimated bool
+}
+
+func (p Page[T]) HasNext() bool {
+  return p.Number < p.Pages
+}
+
+// FetchPage fetches page number (1-based) of size rows of b together with
+// the total number of rows, using COUNT(*) OVER() so both come from one query.
+// Only past the last page, where no row carries the total, a separate count
+// is run. b should be ordered for pages to be stable. With EstimateTotal a
+// large total is estimated instead.
+func FetchPage[T any](ctx context.Context, db *PgxDB, b goqdsl.Builder, number, size int, opts ...ExecOption) (Page[T], error) {
+
+  page := Page[T]{Number: number, Size: size}
+  if number < 1 || size < 1 {
+    return page, fmt.Errorf("goqdslpgx: invalid page %d of size %d", number, size)
+  }
+
+  limit := ") AS page LIMIT " + strconv.Itoa(size) + " OFFSET " + strconv.Itoa((number-1)*size)
+
+  var o execOptions
+  for _, opt := range opts {
+    opt(&o)
+  }
+  if o.estimateAbove > 0 {
+    estimate, err := db.planRows(ctx, b, opts)
+    if err != nil {
+      return page, err
+    }
+    if estimate > o.estimateAbove {
+      page.Items, err = FetchAll[T](ctx, db, wrapped{b, "SELECT page.* FROM (", limit}, opts...)
+      page.Total, page.Estimated = estimate, true
+      page.Pages = int((page.Total + int64(size) - 1) / int64(size))
+      return page, err
+    }
+  }
+
+  paged := wrapped{b, "SELECT page.*, COUNT(*) OVER() AS goqdsl_total FROM (", limit}
+
+  err := db.retrying(ctx, func() error {
+    rows, err := db.run(ctx, OpQuery, paged, opts)
+    if err != nil {
+      return err
+    }
+    page.Items, err = pgx.CollectRows(totalRows{rows.Rows, &page.Total}, rowTo[T](db, false))
+    return err
+  })
+  if err != nil {
+    return page, err
+  }
+
+  if len(page.Items) == 0 && number > 1 {
+    if page.Total, err = db.Count(ctx, b, opts...); err != nil {
+      return page, err
+    }
+  }
+  page.Pages = int((page.Total + int64(size) - 1) / int64(size))
+  return page, nil
+}
+
+// totalRows hides the trailing goqdsl_total column from row scanners and
+// scans it into total.
+type totalRows struct {
+  pgx.Rows
+  total *int64
+}
+
+func (r totalRows) FieldDescriptions() []pgconn.FieldDescription {
+  fields := r.Rows.FieldDescriptions()
+  return fields[:len(fields)-1]
+}
+
+func (r totalRows) Scan(dest ...any) error {
+  if len(dest) == 1 {
+    if scanner, ok := dest[0].(pgx.RowScanner); ok {
+      return scanner.ScanRow(r)
+    }
+  }
+  return r.Rows.Scan(append(dest, r.total)...)
+}
+
+func (r totalRows) Values() ([]any, error) {
+  values, err := r.Rows.Values()
+  if err != nil {
+    return nil, err
+  }
+  return values[:len(values)-1], nil
+}
+
+func (r totalRows) RawValues() [][]byte {
+  values := r.Rows.RawValues()
+  return values[:len(values)-1]
+}
+
+// end
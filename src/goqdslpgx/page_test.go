@@ -0,0 +1,61 @@
+package goqdslpgx
+
+import (
+	"context"
+	"testing"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+func TestFetchPage(t *testing.T) {
+  rec := &recorder{rows: &fakeRows{
+    columns: []string{"uuid", "name", "goqdsl_total"},
+    data: [][]any{{"d3b2aa81", "bar", int64(5)}, {"8f1c2e04", "baz", int64(5)}},
+  }}
+
+  page, err := FetchPage[foo](context.Background(), Wrap(rec), goqdsl.NewQ().Select("uuid", "name").From("foo"), 2, 2)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  expected := "SELECT page.*, COUNT(*) OVER() AS goqdsl_total FROM (SELECT uuid, name FROM foo) AS page LIMIT 2 OFFSET 2"
+  if rec.sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, rec.sql)
+  }
+  if len(page.Items) != 2 || page.Items[1].Name != "baz" {
+    t.Errorf("unexpected items: %v", page.Items)
+  }
+  if page.Total != 5 || page.Pages != 3 || !page.HasNext() {
+    t.Errorf("unexpected page: %+v", page)
+  }
+}
+
+func TestFetchPagePastEnd(t *testing.T) {
+  var log []string
+  answer := func(ctx context.Context, call *Call, next Next) (Result, error) {
+    log = append(log, call.SQL)
+    if len(log) == 1 {
+      return Result{Rows: &fakeRows{columns: []string{"uuid", "name", "goqdsl_total"}}}, nil
+    }
+    return Result{Rows: &fakeRows{columns: []string{"count"}, data: [][]any{{int64(5)}}}}, nil
+  }
+
+  page, err := FetchPage[foo](context.Background(), Wrap(&recorder{}).Use(answer), goqdsl.NewQ().Select("uuid", "name").From("foo"), 9, 2)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(log) != 2 || log[1] != "SELECT COUNT(*) FROM (SELECT uuid, name FROM foo) AS count" {
+    t.Errorf("expected a fallback count, got %v", log)
+  }
+  if len(page.Items) != 0 || page.Total != 5 || page.Pages != 3 || page.HasNext() {
+    t.Errorf("unexpected page: %+v", page)
+  }
+}
+
+func TestFetchPageInvalid(t *testing.T) {
+  if _, err := FetchPage[foo](context.Background(), Wrap(&recorder{}), goqdsl.NewQ().From("foo"), 0, 10); err == nil {
+    t.Error("expected an error for page 0")
+  }
+}
+
+// end
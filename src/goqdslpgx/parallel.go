@@ -0,0 +1,82 @@
+package goqdslpgx
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	goqdsl "github.com/raugustinus/goqdsl/src"
+	"golang.org/x/sync/errgroup"
+)
+
+// Group runs independent fetches concurrently on the pool. The first error
+// cancels the others. Results are valid after Wait returns nil.
+type Group struct {
+  db *PgxDB
+  ctx context.Context
+  eg *errgroup.Group
+}
+
+// Parallel starts a Group. Only a pool runs statements concurrently; on a
+// transaction or a single connection the fetches run one after another.
+func (db *PgxDB) Parallel(ctx context.Context) *Group {
+  eg, ctx := errgroup.WithContext(ctx)
+  if _, ok := db.q.(*pgxpool.Pool); !ok {
+    eg.SetLimit(1)
+  }
+  return &Group{db: db, ctx: ctx, eg: eg}
+}
+
+func (g *Group) Wait() error {
+  return g.eg.Wait()
+}
+
+// GoAll adds a FetchAll to g.
+func GoAll[T any](g *Group, b goqdsl.Builder, opts ...ExecOption) *[]T {
+  values := new([]T)
+  g.eg.Go(func() (err error) {
+    *values, err = FetchAll[T](g.ctx, g.db, b, opts...)
+    return err
+  })
+  return values
+}
+
+// GoOne adds a FetchOne to g.
+func GoOne[T any](g *Group, b goqdsl.Builder, opts ...ExecOption) *T {
+  value := new(T)
+  g.eg.Go(func() (err error) {
+    *value, err = FetchOne[T](g.ctx, g.db, b, opts...)
+    return err
+  })
+  return value
+}
+
+// GoScalar adds a FetchScalar to g.
+func GoScalar[T any](g *Group, b goqdsl.Builder, opts ...ExecOption) *T {
+  value := new(T)
+  g.eg.Go(func() (err error) {
+    *value, err = FetchScalar[T](g.ctx, g.db, b, opts...)
+    return err
+  })
+  return value
+}
+
+// FetchAllParallel runs builders returning the same type concurrently, with
+// results in the order of builders.
+func FetchAllParallel[T any](ctx context.Context, db *PgxDB, builders ...goqdsl.Builder) ([][]T, error) {
+  g := db.Parallel(ctx)
+  results := make([]*[]T, len(builders))
+  for i, b := range builders {
+    results[i] = GoAll[T](g, b)
+  }
+  if err := g.Wait(); err != nil {
+    return nil, err
+  }
+
+  values := make([][]T, len(results))
+  for i, r := range results {
+    values[i] = *r
+  }
+  return values, nil
+}
+
+// end
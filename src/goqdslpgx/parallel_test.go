@@ -0,0 +1,94 @@
+package goqdslpgx
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+// answer serves every query from fresh rows, so concurrent calls do not
+// share state.
+func answer(ctx context.Context, call *Call, next Next) (Result, error) {
+  switch {
+  case strings.Contains(call.SQL, "COUNT"):
+    return Result{Rows: &fakeRows{columns: []string{"count"}, data: [][]any{{int64(3)}}}}, nil
+  case strings.Contains(call.SQL, "fail"):
+    return Result{}, errors.New("boom")
+  case strings.Contains(call.SQL, "wait"):
+    <-ctx.Done()
+    return Result{}, ctx.Err()
+  }
+  return Result{Rows: fooRows()}, nil
+}
+
+func TestParallel(t *testing.T) {
+  db := Wrap(&recorder{}).Use(answer)
+  q := goqdsl.NewQ().Select("uuid", "name").From("foo")
+
+  g := db.Parallel(context.Background())
+  all := GoAll[foo](g, q)
+  one := GoOne[foo](g, q)
+  count := GoScalar[int64](g, goqdsl.NewQ().Select("COUNT(*)").From("foo"))
+  if err := g.Wait(); err != nil {
+    t.Fatal(err)
+  }
+  if len(*all) != 3 || one.Name != "bar" || *count != 3 {
+    t.Errorf("unexpected results: %v %v %d", *all, *one, *count)
+  }
+}
+
+func TestParallelCancels(t *testing.T) {
+  pool, err := pgxpool.New(context.Background(), "postgres://localhost/foo")
+  if err != nil {
+    t.Fatal(err)
+  }
+  defer pool.Close()
+  db := New(pool).Use(answer)
+
+  _, err = FetchAllParallel[foo](context.Background(), db,
+    goqdsl.NewQ().Select("uuid").From("wait"),
+    goqdsl.NewQ().Select("uuid").From("fail"))
+  if err == nil || err.Error() != "boom" {
+    t.Errorf("expected the first error, got %v", err)
+  }
+}
+
+func TestParallelSequential(t *testing.T) {
+  var running, most atomic.Int32
+  track := func(ctx context.Context, call *Call, next Next) (Result, error) {
+    n := running.Add(1)
+    defer running.Add(-1)
+    if n > most.Load() {
+      most.Store(n)
+    }
+    return answer(ctx, call, next)
+  }
+  q := goqdsl.NewQ().Select("uuid", "name").From("foo")
+
+  if _, err := FetchAllParallel[foo](context.Background(), Wrap(&recorder{}).Use(track), q, q, q); err != nil {
+    t.Fatal(err)
+  }
+  if n := most.Load(); n != 1 {
+    t.Errorf("expected one fetch at a time on a single connection, got %d", n)
+  }
+}
+
+func TestFetchAllParallel(t *testing.T) {
+  db := Wrap(&recorder{}).Use(answer)
+  q := goqdsl.NewQ().Select("uuid", "name").From("foo")
+
+  results, err := FetchAllParallel[foo](context.Background(), db, q, q)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(results) != 2 || len(results[1]) != 3 {
+    t.Errorf("unexpected results: %v", results)
+  }
+}
+
+// end
@@ -0,0 +1,31 @@
+package goqdslpgx
+
+import (
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// MaxParams is the most bind parameters sent in one statement, PostgreSQL's
+// wire protocol limit by default.
+var MaxParams = 65535
+
+// TooManyParamsError is returned before sending a statement with more bind
+// parameters than MaxParams.
+type TooManyParamsError struct {
+  Params int
+  Max int
+}
+
+func (e *TooManyParamsError) Error() string {
+  return fmt.Sprintf("goqdslpgx: statement has %d bind parameters, the limit is %d", e.Params, e.Max)
+}
+
+func checkParams(args pgx.NamedArgs) error {
+  if len(args) > MaxParams {
+    return &TooManyParamsError{Params: len(args), Max: MaxParams}
+  }
+  return nil
+}
+
+// end
@@ -0,0 +1,35 @@
+package goqdslpgx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+func TestTooManyParams(t *testing.T) {
+  defer func(n int) { MaxParams = n }(MaxParams)
+  MaxParams = 3
+
+  rec := &recorder{}
+  q := goqdsl.Upsert("foo", []string{"uuid", "name"}).Row("1", "a").Row("2", "b")
+
+  var paramsErr *TooManyParamsError
+  if _, err := Wrap(rec).Exec(context.Background(), q); !errors.As(err, &paramsErr) || paramsErr.Params != 4 {
+    t.Errorf("expected TooManyParamsError, got %v", err)
+  }
+  if _, err := Wrap(rec).Batch(context.Background(), q); !errors.As(err, &paramsErr) {
+    t.Errorf("expected TooManyParamsError from Batch, got %v", err)
+  }
+  if len(rec.log) != 0 {
+    t.Errorf("expected nothing sent, got %v", rec.log)
+  }
+
+  n, err := UpsertAll(context.Background(), Wrap(rec), "foo", []foo{{"1", "a"}, {"2", "b"}}, "uuid")
+  if err != nil || len(rec.log) != 2 {
+    t.Errorf("expected UpsertAll to chunk below the limit, got %d %v %v", n, err, rec.log)
+  }
+}
+
+// end
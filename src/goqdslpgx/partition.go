@@ -0,0 +1,75 @@
+package goqdslpgx
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+// Partitions returns the names, without schema, of the partitions of parent.
+func (db *PgxDB) Partitions(ctx context.Context, parent string, opts ...ExecOption) ([]string, error) {
+  return FetchColumn[string](ctx, db, partitionsQ(parent), opts...)
+}
+
+// EnsurePartitions creates the partitions of parent laid out by period from
+// the one holding from up to the one holding to, skipping existing ones.
+func (db *PgxDB) EnsurePartitions(ctx context.Context, parent string, period goqdsl.PartitionPeriod, from, to time.Time, opts ...ExecOption) error {
+  for _, q := range period.Range(parent, from, to) {
+    if _, err := db.Exec(ctx, q, opts...); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+
+// DropPartitionsBefore detaches and drops the partitions of parent laid out
+// by period that end at or before cutoff, each in its own transaction, and
+// returns the ones it dropped.
+func (db *PgxDB) DropPartitionsBefore(ctx context.Context, parent string, period goqdsl.PartitionPeriod, cutoff time.Time, opts ...ExecOption) ([]string, error) {
+
+  names, err := db.Partitions(ctx, parent, opts...)
+  if err != nil {
+    return nil, err
+  }
+
+  schema, base := "", parent
+  if i := strings.LastIndexByte(parent, '.'); i >= 0 {
+    schema, base = parent[:i+1], parent[i+1:]
+  }
+
+  var dropped []string
+  for _, name := range period.Before(base, names, cutoff) {
+    name = schema + name
+    err := db.Tx(ctx, func(tx *PgxDB) error {
+      if _, err := tx.Exec(ctx, goqdsl.DetachPartition(parent, name), opts...); err != nil {
+        return err
+      }
+      _, err := tx.Exec(ctx, goqdsl.DropTable(name), opts...)
+      return err
+    })
+    if err != nil {
+      return dropped, err
+    }
+    dropped = append(dropped, name)
+  }
+  return dropped, nil
+}
+
+// partitionsQ lists the partitions of a table.
+type partitionsQ string
+
+func (p partitionsQ) BuildNamed() (string, map[string]any) {
+  return "SELECT c.relname::text FROM pg_inherits i JOIN pg_class c ON c.oid = i.inhrelid WHERE i.inhparent = to_regclass(@parent) ORDER BY c.relname", map[string]any{"parent": string(p)}
+}
+
+func (p partitionsQ) BuildPositional() (string, []any) {
+  return "SELECT c.relname::text FROM pg_inherits i JOIN pg_class c ON c.oid = i.inhrelid WHERE i.inhparent = to_regclass($1) ORDER BY c.relname", []any{string(p)}
+}
+
+func (p partitionsQ) Query() string {
+  return goqdsl.ToSQL(p)
+}
+
+// end
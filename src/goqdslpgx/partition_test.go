@@ -0,0 +1,53 @@
+package goqdslpgx
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+func TestEnsurePartitions(t *testing.T) {
+  rec := &recorder{}
+  from := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+
+  if err := Wrap(rec).EnsurePartitions(context.Background(), "events", goqdsl.PartitionMonthly, from, from.AddDate(0, 2, 0)); err != nil {
+    t.Fatal(err)
+  }
+  expected := []string{
+    "CREATE TABLE IF NOT EXISTS events_202403 PARTITION OF events FOR VALUES FROM ('2024-03-01T00:00:00Z') TO ('2024-04-01T00:00:00Z')",
+    "CREATE TABLE IF NOT EXISTS events_202404 PARTITION OF events FOR VALUES FROM ('2024-04-01T00:00:00Z') TO ('2024-05-01T00:00:00Z')",
+  }
+  if !reflect.DeepEqual(rec.log, expected) {
+    t.Errorf("expected:\n%v\ngot:\n%v", expected, rec.log)
+  }
+}
+
+func TestDropPartitionsBefore(t *testing.T) {
+  rec := &recorder{rows: &fakeRows{columns: []string{"relname"}, data: [][]any{{"events_202401"}, {"events_202402"}, {"events_202403"}, {"events_default"}}}}
+
+  dropped, err := Wrap(rec).DropPartitionsBefore(context.Background(), "app.events", goqdsl.PartitionMonthly, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC))
+  if err != nil {
+    t.Fatal(err)
+  }
+  if !reflect.DeepEqual(dropped, []string{"app.events_202401", "app.events_202402"}) {
+    t.Errorf("unexpected dropped partitions: %v", dropped)
+  }
+  expected := []string{
+    "SELECT c.relname::text FROM pg_inherits i JOIN pg_class c ON c.oid = i.inhrelid WHERE i.inhparent = to_regclass(@parent) ORDER BY c.relname",
+    "ALTER TABLE app.events DETACH PARTITION app.events_202401",
+    "DROP TABLE app.events_202401",
+    "ALTER TABLE app.events DETACH PARTITION app.events_202402",
+    "DROP TABLE app.events_202402",
+  }
+  if !reflect.DeepEqual(rec.log, expected) {
+    t.Errorf("expected:\n%v\ngot:\n%v", expected, rec.log)
+  }
+  if !rec.committed {
+    t.Error("expected the drops to be committed")
+  }
+}
+
+// end
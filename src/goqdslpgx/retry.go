@@ -0,0 +1,86 @@
+package goqdslpgx
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type RetryPolicy struct {
+  // MaxAttempts includes the first attempt.
+  MaxAttempts int
+  // Backoff doubles after every failed attempt, up to MaxBackoff.
+  Backoff time.Duration
+  MaxBackoff time.Duration
+  // Retryable overrides IsTransient.
+  Retryable func(error) bool
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+  MaxAttempts: 3,
+  Backoff: 20 * time.Millisecond,
+  MaxBackoff: time.Second,
+}
+
+func (db *PgxDB) Retry(policy RetryPolicy) *PgxDB {
+  db.retry = &policy
+  return db
+}
+
+// IsTransient reports serialization failures, deadlocks and connection
+// failures that did not send anything to the server.
+func IsTransient(err error) bool {
+
+  var pgErr *pgconn.PgError
+  if errors.As(err, &pgErr) {
+    switch pgErr.Code {
+    case "40001", "40P01":
+      return true
+    }
+    // class 08: connection exception
+    return len(pgErr.Code) == 5 && pgErr.Code[:2] == "08"
+  }
+  return pgconn.SafeToRetry(err)
+}
+
+// retrying runs fn under the retry policy. Inside a transaction fn runs once,
+// a failed statement has aborted the transaction and only Tx can retry it.
+func (db *PgxDB) retrying(ctx context.Context, fn func() error) error {
+  return mapError(db.retryLoop(ctx, fn))
+}
+
+func (db *PgxDB) retryLoop(ctx context.Context, fn func() error) error {
+
+  if db.retry == nil || db.inTx {
+    return fn()
+  }
+
+  retryable := db.retry.Retryable
+  if retryable == nil {
+    retryable = IsTransient
+  }
+
+  backoff := db.retry.Backoff
+  for attempt := 1; ; attempt++ {
+
+    err := fn()
+    if err == nil || attempt >= db.retry.MaxAttempts || !retryable(err) {
+      return err
+    }
+
+    select {
+    case <-ctx.Done():
+      return err
+    case <-time.After(backoff):
+    }
+
+    backoff *= 2
+    if db.retry.MaxBackoff > 0 && backoff > db.retry.MaxBackoff {
+      backoff = db.retry.MaxBackoff
+    }
+  }
+}
+
+// end
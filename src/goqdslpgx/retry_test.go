@@ -0,0 +1,70 @@
+package goqdslpgx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+func TestIsTransient(t *testing.T) {
+  tests := []struct {
+    err error
+    expected bool
+  }{
+    {&pgconn.PgError{Code: "40001"}, true},
+    {&pgconn.PgError{Code: "40P01"}, true},
+    {&pgconn.PgError{Code: "08006"}, true},
+    {&pgconn.PgError{Code: "23505"}, false},
+    {errors.New("boom"), false},
+  }
+  for _, test := range tests {
+    if IsTransient(test.err) != test.expected {
+      t.Errorf("IsTransient(%v): expected %v", test.err, test.expected)
+    }
+  }
+}
+
+func TestRetry(t *testing.T) {
+  attempts := 0
+  failing := func(ctx context.Context, call *Call, next Next) (Result, error) {
+    attempts++
+    if attempts < 3 {
+      return Result{}, &pgconn.PgError{Code: "40001"}
+    }
+    return next(ctx, call)
+  }
+
+  db := Wrap(&recorder{}).Use(failing).Retry(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond})
+  if _, err := db.Exec(context.Background(), goqdsl.RefreshMaterializedView("foo_names")); err != nil {
+    t.Fatal(err)
+  }
+  if attempts != 3 {
+    t.Errorf("expected 3 attempts, got %d", attempts)
+  }
+}
+
+func TestRetryNotInsideTx(t *testing.T) {
+  rec := &recorder{}
+  statements, transactions := 0, 0
+  failing := func(ctx context.Context, call *Call, next Next) (Result, error) {
+    statements++
+    return Result{}, &pgconn.PgError{Code: "40001"}
+  }
+
+  db := Wrap(rec).Use(failing).Retry(RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond})
+  err := db.Tx(context.Background(), func(tx *PgxDB) error {
+    transactions++
+    _, err := tx.Exec(context.Background(), goqdsl.RefreshMaterializedView("foo_names"))
+    return err
+  })
+  if err == nil {
+    t.Fatal("expected error")
+  }
+  if transactions != 2 || statements != 2 {
+    t.Errorf("expected the transaction to be retried once, got %d transactions and %d statements", transactions, statements)
+  }
+}
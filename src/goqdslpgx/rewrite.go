@@ -0,0 +1,86 @@
+package goqdslpgx
+
+import (
+	"context"
+	"reflect"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+// A Rewriter returns the builder to execute in place of b. It must not
+// modify b, clone it instead, see goqdsl.Q.Clone.
+type Rewriter func(b goqdsl.Builder) goqdsl.Builder
+
+// Rewrite adds rewriters applied, in order, to every builder before it is
+// built, including those sent with Batch.
+func (db *PgxDB) Rewrite(rewriters ...Rewriter) *PgxDB {
+  db.rewriters = append(db.rewriters, rewriters...)
+  return db
+}
+
+// rewrite binds table templates, runs the rewriters and then the tenancy
+// filter, which matches tables with or without schema, so a rewriter cannot
+// drop the tenant, and checks identifiers of the result. A bound prepared builder is rewritten through its source.
+func (db *PgxDB) rewrite(ctx context.Context, b goqdsl.Builder) (goqdsl.Builder, error) {
+  if w, ok := b.(wrapped); ok {
+    inner, err := db.rewrite(ctx, w.inner)
+    w.inner = inner
+    return w, err
+  }
+  if bound, ok := b.(*goqdsl.Bound); ok {
+    src, err := db.rewrite(ctx, bound.Source())
+    if err != nil || same(src, bound.Source()) {
+      return bound, err
+    }
+    return goqdsl.Prepare(src).Bind(bound.Values())
+  }
+  b, err := bindTables(ctx, b)
+  if err != nil {
+    return nil, err
+  }
+  for _, rw := range db.rewriters {
+    b = rw(b)
+  }
+  b, err = db.tenancy.apply(ctx, b)
+  if err == nil && db.strict {
+    err = goqdsl.CheckIdentifiers(b)
+  }
+  return b, err
+}
+
+// same reports whether a rewrite left b as it was. A prepared builder is only
+// prepared again when it was not.
+func same(a, b goqdsl.Builder) bool {
+  t := reflect.TypeOf(a)
+  return t == reflect.TypeOf(b) && t.Comparable() && a == b
+}
+
+// StrictIdentifiers rejects builders whose table or column names fail
+// goqdsl.CheckIdentifiers before they are sent.
+func (db *PgxDB) StrictIdentifiers() *PgxDB {
+  db.strict = true
+  return db
+}
+
+// SelectsOn returns a Rewriter applying fn to selects from one of tables.
+func SelectsOn(fn func(q *goqdsl.Q) *goqdsl.Q, tables ...string) Rewriter {
+  match := map[string]bool{}
+  for _, t := range tables {
+    match[t] = true
+  }
+  return func(b goqdsl.Builder) goqdsl.Builder {
+    if q, ok := b.(*goqdsl.Q); ok && match[q.Table()] {
+      return fn(q.Clone())
+    }
+    return b
+  }
+}
+
+// Schema prefixes selects from tables with schema.
+func Schema(schema string, tables ...string) Rewriter {
+  return SelectsOn(func(q *goqdsl.Q) *goqdsl.Q {
+    return q.From(schema + "." + q.Table())
+  }, tables...)
+}
+
+// end
@@ -0,0 +1,72 @@
+package goqdslpgx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+func TestRewrite(t *testing.T) {
+  rec := &recorder{}
+  active := SelectsOn(func(q *goqdsl.Q) *goqdsl.Q {
+    return q.And("active", "true")
+  }, "foo")
+  db := Wrap(rec).Rewrite(active, Schema("app", "foo"))
+
+  q := goqdsl.NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "bar"})
+  if _, err := db.Exec(context.Background(), q); err != nil {
+    t.Fatal(err)
+  }
+
+  expected := "SELECT uuid FROM app.foo WHERE active = @active AND   name = @name "
+  if rec.sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, rec.sql)
+  }
+  if args := rec.args[0].(pgx.NamedArgs); args["active"] != "true" {
+    t.Errorf("expected active arg, got %v", args)
+  }
+  if q.Table() != "foo" || q.Query() != "SELECT uuid FROM foo WHERE name = bar " {
+    t.Errorf("rewriter modified the caller's builder: %s", q.Query())
+  }
+}
+
+func TestRewriteSkipsOtherTables(t *testing.T) {
+  rec := &recorder{}
+  db := Wrap(rec).Rewrite(Schema("app", "foo"))
+
+  db.Count(context.Background(), goqdsl.NewQ().Select("uuid").From("bar"))
+  expected := "SELECT COUNT(*) FROM (SELECT uuid FROM bar) AS count"
+  if rec.sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, rec.sql)
+  }
+}
+
+func TestRewriteWrapped(t *testing.T) {
+  rec := &recorder{}
+  db := Wrap(rec).Rewrite(Schema("app", "foo"))
+
+  db.Exists(context.Background(), goqdsl.NewQ().Select("uuid").From("foo"))
+  expected := "SELECT EXISTS (SELECT uuid FROM app.foo)"
+  if rec.sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, rec.sql)
+  }
+}
+
+func TestStrictIdentifiers(t *testing.T) {
+  rec := &recorder{}
+  db := Wrap(rec).StrictIdentifiers()
+
+  var identErr *goqdsl.IdentifierError
+  _, err := db.Exec(context.Background(), goqdsl.NewQ().Select("uuid").From("foo; DROP TABLE foo"))
+  if !errors.As(err, &identErr) || len(rec.log) != 0 {
+    t.Errorf("expected an IdentifierError before sending, got %v %v", err, rec.log)
+  }
+  if _, err := db.Exec(context.Background(), goqdsl.NewQ().Select("uuid").From("foo")); err != nil {
+    t.Error(err)
+  }
+}
+
+// end
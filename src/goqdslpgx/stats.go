@@ -0,0 +1,83 @@
+package goqdslpgx
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// recentQueries is how many of the latest statements Stats picks the slowest
+// from.
+const recentQueries = 256
+
+type Stats struct {
+  Pool *pgxpool.Stat // nil unless the PgxDB wraps a pool
+  InFlight int64
+  Slowest []QueryStats // slowest of the recent statements, slowest first
+}
+
+type monitor struct {
+  inFlight atomic.Int64
+  mu sync.Mutex
+  recent [recentQueries]QueryStats
+  next int
+}
+
+// Stats reports the pool, the statements in flight and the n slowest of the
+// recent ones. Queries are in flight until their rows are read or closed.
+func (db *PgxDB) Stats(n int) Stats {
+
+  s := Stats{InFlight: db.monitor.inFlight.Load()}
+  if db.pool != nil {
+    s.Pool = db.pool.Stat()
+  }
+
+  db.monitor.mu.Lock()
+  for _, q := range db.monitor.recent {
+    if q.Op != "" {
+      s.Slowest = append(s.Slowest, q)
+    }
+  }
+  db.monitor.mu.Unlock()
+
+  sort.Slice(s.Slowest, func(i, j int) bool { return s.Slowest[i].Duration > s.Slowest[j].Duration })
+  if len(s.Slowest) > n {
+    s.Slowest = s.Slowest[:n]
+  }
+  return s
+}
+
+// Ping checks the database is reachable, for readiness probes.
+func (db *PgxDB) Ping(ctx context.Context) error {
+  if db.pool != nil {
+    return db.pool.Ping(ctx)
+  }
+  _, err := db.q.Exec(ctx, "SELECT 1")
+  return err
+}
+
+// track counts call as in flight until the returned function is called with
+// its outcome.
+func (m *monitor) track(ctx context.Context, call *Call) func(error) {
+
+  m.inFlight.Add(1)
+  stats := QueryStats{Name: queryName(ctx, call), Op: call.Op}
+  start := time.Now()
+
+  return func(err error) {
+    m.inFlight.Add(-1)
+    stats.Duration = time.Since(start)
+    stats.Err = err
+
+    m.mu.Lock()
+    m.recent[m.next] = stats
+    m.next = (m.next + 1) % recentQueries
+    m.mu.Unlock()
+  }
+}
+
+// end
@@ -0,0 +1,52 @@
+package goqdslpgx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+func TestStats(t *testing.T) {
+  slow := func(ctx context.Context, call *Call, next Next) (Result, error) {
+    if name, _ := ctx.Value(queryNameKey{}).(string); name == "slow" {
+      time.Sleep(5 * time.Millisecond)
+    }
+    return next(ctx, call)
+  }
+  rec := &recorder{rows: fooRows()}
+  db := Wrap(rec).Use(slow)
+  q := goqdsl.NewQ().Select("uuid", "name").From("foo")
+
+  db.Exec(context.Background(), q)
+  db.Exec(WithQueryName(context.Background(), "slow"), q)
+
+  rows, err := db.Query(context.Background(), q)
+  if err != nil {
+    t.Fatal(err)
+  }
+  inFlight := db.Stats(1).InFlight
+  rows.Close()
+
+  if inFlight != 1 {
+    t.Errorf("expected the open query in flight, got %d", inFlight)
+  }
+
+  s := db.Stats(2)
+  if s.InFlight != 0 || s.Pool != nil {
+    t.Errorf("unexpected stats: %+v", s)
+  }
+  if len(s.Slowest) != 2 || s.Slowest[0].Name != "slow" {
+    t.Errorf("expected the slow query first, got %+v", s.Slowest)
+  }
+}
+
+func TestPing(t *testing.T) {
+  rec := &recorder{}
+  if err := Wrap(rec).Ping(context.Background()); err != nil || rec.sql != "SELECT 1" {
+    t.Errorf("unexpected ping: %q %v", rec.sql, err)
+  }
+}
+
+// end
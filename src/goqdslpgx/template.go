@@ -0,0 +1,27 @@
+package goqdslpgx
+
+import (
+	"context"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+type tableVarsKey struct{}
+
+// WithTableVars sets the values for the placeholders of table templates, see
+// goqdsl.Template, in statements run with ctx. A templated statement without
+// a value for each placeholder fails.
+func WithTableVars(ctx context.Context, vars map[string]string) context.Context {
+  return context.WithValue(ctx, tableVarsKey{}, vars)
+}
+
+func bindTables(ctx context.Context, b goqdsl.Builder) (goqdsl.Builder, error) {
+  q, ok := b.(*goqdsl.Q)
+  if !ok {
+    return b, nil
+  }
+  vars, _ := ctx.Value(tableVarsKey{}).(map[string]string)
+  return q.Bind(vars)
+}
+
+// end
@@ -0,0 +1,29 @@
+package goqdslpgx
+
+import (
+	"context"
+	"testing"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+func TestTableVars(t *testing.T) {
+  rec := &recorder{}
+  db := Wrap(rec)
+  q := goqdsl.NewQ().Select("uuid").From(goqdsl.Template("events_{{shard}}"))
+
+  ctx := WithTableVars(context.Background(), map[string]string{"shard": "3"})
+  if _, err := db.Exec(ctx, q); err != nil {
+    t.Fatal(err)
+  }
+  if rec.sql != "SELECT uuid FROM events_3 " {
+    t.Errorf("unexpected sql: %s", rec.sql)
+  }
+
+  rec.log = nil
+  if _, err := db.Exec(context.Background(), q); err == nil || len(rec.log) != 0 {
+    t.Errorf("expected an error before sending, got %v with %v", err, rec.log)
+  }
+}
+
+// end
@@ -0,0 +1,125 @@
+package goqdslpgx
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+var ErrNoTenant = errors.New("goqdslpgx: no tenant in context")
+
+type tenantKey struct{}
+
+type noTenantKey struct{}
+
+type tenancy struct {
+  column string
+  tables map[string]bool
+}
+
+// WithTenant sets the tenant that Tenancy filters on.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+  return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// WithoutTenant lets statements run in ctx on tenant tables unfiltered, e.g.
+// for admin reports or migrations.
+func WithoutTenant(ctx context.Context) context.Context {
+  return context.WithValue(ctx, noTenantKey{}, true)
+}
+
+// Tenancy adds column = tenant to every select on tables, with the tenant
+// taken from the context, see WithTenant, for the table selected from and for
+// each joined one. Tables match with or without schema, so a Schema rewrite
+// keeps the filter. The column is qualified with the alias of the table, if
+// any. A select on one of the tables without a tenant fails with ErrNoTenant
+// unless the context is marked with WithoutTenant. The select of CreateTableAs
+// and CreateMaterializedView is filtered the same way.
+func (db *PgxDB) Tenancy(column string, tables ...string) *PgxDB {
+  t := &tenancy{column: column, tables: map[string]bool{}}
+  for _, table := range tables {
+    t.tables[table] = true
+  }
+  db.tenancy = t
+  return db
+}
+
+func (t *tenancy) apply(ctx context.Context, b goqdsl.Builder) (goqdsl.Builder, error) {
+  if t == nil {
+    return b, nil
+  }
+  switch b := b.(type) {
+  case *goqdsl.Q:
+    q, err := t.filter(ctx, b)
+    if err != nil {
+      return nil, err
+    }
+    return q, nil
+  case *goqdsl.CreateTableAsQ:
+    q, err := t.filter(ctx, b.Source())
+    if err != nil || q == b.Source() {
+      return b, err
+    }
+    return b.WithSource(q), nil
+  case *goqdsl.MaterializedViewQ:
+    q, err := t.filter(ctx, b.Source())
+    if err != nil || q == b.Source() {
+      return b, err
+    }
+    return b.WithSource(q), nil
+  }
+  return b, nil
+}
+
+// filter returns q with the tenant filter, or q itself when none applies.
+func (t *tenancy) filter(ctx context.Context, q *goqdsl.Q) (*goqdsl.Q, error) {
+
+  var columns []string
+  if t.covers(q.Table()) {
+    columns = append(columns, t.qualify(q.Alias()))
+  }
+  for _, j := range q.Joins() {
+    ref := strings.Fields(j.Table())
+    if len(ref) == 0 || !t.covers(ref[0]) {
+      continue
+    }
+    columns = append(columns, t.qualify(ref[len(ref)-1]))
+  }
+  if len(columns) == 0 {
+    return q, nil
+  }
+  if skip, _ := ctx.Value(noTenantKey{}).(bool); skip {
+    return q, nil
+  }
+
+  tenant, ok := ctx.Value(tenantKey{}).(string)
+  if !ok {
+    return nil, ErrNoTenant
+  }
+  c := q.Clone()
+  for _, column := range columns {
+    c.And(column, tenant)
+  }
+  return c, nil
+}
+
+// covers reports whether table, possibly schema qualified, is filtered.
+func (t *tenancy) covers(table string) bool {
+  if t.tables[table] {
+    return true
+  }
+  name := table[strings.LastIndexByte(table, '.')+1:]
+  return t.tables[name] || t.tables[strings.Trim(name, `"`)]
+}
+
+// qualify qualifies the tenant column with the alias or table ref.
+func (t *tenancy) qualify(ref string) string {
+  if ref == "" || strings.Contains(t.column, ".") {
+    return t.column
+  }
+  return ref + "." + t.column
+}
+
+// end
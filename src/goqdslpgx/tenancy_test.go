@@ -0,0 +1,170 @@
+package goqdslpgx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+func TestTenancy(t *testing.T) {
+  rec := &recorder{}
+  db := Wrap(rec).Tenancy("tenant_id", "foo")
+
+  ctx := WithTenant(context.Background(), "acme")
+  if _, err := db.Exec(ctx, goqdsl.NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "bar"})); err != nil {
+    t.Fatal(err)
+  }
+
+  expected := "SELECT uuid FROM foo WHERE name = @name AND   tenant_id = @tenant_id "
+  if rec.sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, rec.sql)
+  }
+  if args := rec.args[0].(pgx.NamedArgs); args["tenant_id"] != "acme" {
+    t.Errorf("expected tenant arg, got %v", args)
+  }
+}
+
+func TestTenancyAlias(t *testing.T) {
+  rec := &recorder{}
+  db := Wrap(rec).Tenancy("tenant_id", "foo")
+
+  ctx := WithTenant(context.Background(), "acme")
+  if _, err := db.Exec(ctx, goqdsl.NewQ().Select("f.uuid").FromTable(goqdsl.T("foo").As("f"))); err != nil {
+    t.Fatal(err)
+  }
+
+  expected := "SELECT f.uuid FROM foo f WHERE f.tenant_id = @f_tenant_id "
+  if rec.sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, rec.sql)
+  }
+}
+
+func TestTenancySchema(t *testing.T) {
+  rec := &recorder{}
+  db := Wrap(rec).Rewrite(Schema("app", "foo")).Tenancy("tenant_id", "foo")
+
+  if _, err := db.Exec(WithTenant(context.Background(), "acme"), goqdsl.NewQ().Select("uuid").From("foo")); err != nil {
+    t.Fatal(err)
+  }
+  expected := "SELECT uuid FROM app.foo WHERE tenant_id = @tenant_id "
+  if rec.sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, rec.sql)
+  }
+  if _, err := db.Exec(context.Background(), goqdsl.NewQ().Select("uuid").From("foo")); !errors.Is(err, ErrNoTenant) {
+    t.Errorf("expected ErrNoTenant, got %v", err)
+  }
+}
+
+func TestTenancyJoin(t *testing.T) {
+  rec := &recorder{}
+  db := Wrap(rec).Tenancy("tenant_id", "foo")
+  bars, foos := goqdsl.T("bar").As("b"), goqdsl.T("app.foo").As("f")
+  q := goqdsl.NewQ().Select("b.uuid").FromTable(bars).InnerJoin([]goqdsl.Join{foos.On("b.foo_uuid", "f.uuid")})
+
+  if _, err := db.Exec(WithTenant(context.Background(), "acme"), q); err != nil {
+    t.Fatal(err)
+  }
+  expected := "SELECT b.uuid FROM bar b INNER JOIN app.foo f ON b.foo_uuid = f.uuid WHERE f.tenant_id = @f_tenant_id "
+  if rec.sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, rec.sql)
+  }
+  if _, err := db.Exec(context.Background(), q); !errors.Is(err, ErrNoTenant) {
+    t.Errorf("expected ErrNoTenant for a join, got %v", err)
+  }
+}
+
+func TestTenancyRequiresTenant(t *testing.T) {
+  rec := &recorder{}
+  db := Wrap(rec).Tenancy("tenant_id", "foo")
+  q := goqdsl.NewQ().Select("uuid").From("foo")
+
+  if _, err := db.Exec(context.Background(), q); !errors.Is(err, ErrNoTenant) {
+    t.Errorf("expected ErrNoTenant, got %v", err)
+  }
+  if _, err := db.Count(context.Background(), q); !errors.Is(err, ErrNoTenant) {
+    t.Errorf("expected ErrNoTenant from Count, got %v", err)
+  }
+  if _, err := db.Batch(context.Background(), q); !errors.Is(err, ErrNoTenant) {
+    t.Errorf("expected ErrNoTenant from Batch, got %v", err)
+  }
+  if len(rec.log) != 0 {
+    t.Errorf("expected nothing sent, got %v", rec.log)
+  }
+
+  if _, err := db.Exec(WithoutTenant(context.Background()), q); err != nil {
+    t.Fatal(err)
+  }
+  if rec.sql != "SELECT uuid FROM foo " {
+    t.Errorf("unexpected sql: %s", rec.sql)
+  }
+}
+
+func TestTenancyWrapped(t *testing.T) {
+  rec := &recorder{}
+  db := Wrap(rec).Tenancy("tenant_id", "foo")
+  q := goqdsl.NewQ().Select("uuid").From("foo")
+
+  for _, b := range []goqdsl.Builder{goqdsl.CreateTableAs("foo_copy", q), goqdsl.CreateMaterializedView("foo_view", q)} {
+    if _, err := db.Exec(context.Background(), b); !errors.Is(err, ErrNoTenant) {
+      t.Errorf("%s: expected ErrNoTenant, got %v", b.Query(), err)
+    }
+  }
+  if len(rec.log) != 0 {
+    t.Errorf("expected nothing sent, got %v", rec.log)
+  }
+
+  ctx := WithTenant(context.Background(), "acme")
+  if _, err := db.Exec(ctx, goqdsl.CreateTableAs("foo_copy", q)); err != nil {
+    t.Fatal(err)
+  }
+  if rec.sql != "CREATE TABLE foo_copy AS SELECT uuid FROM foo WHERE tenant_id = 'acme'" {
+    t.Errorf("unexpected sql: %s", rec.sql)
+  }
+  if _, err := db.Exec(ctx, goqdsl.CreateMaterializedView("foo_view", q).WithNoData()); err != nil {
+    t.Fatal(err)
+  }
+  if rec.sql != "CREATE MATERIALIZED VIEW foo_view AS SELECT uuid FROM foo WHERE tenant_id = 'acme' WITH NO DATA" {
+    t.Errorf("unexpected sql: %s", rec.sql)
+  }
+  if q.Query() != "SELECT uuid FROM foo " {
+    t.Errorf("expected the wrapped query left alone, got %s", q.Query())
+  }
+}
+
+func TestTenancyOtherTables(t *testing.T) {
+  rec := &recorder{}
+  db := Wrap(rec).Tenancy("tenant_id", "foo")
+
+  if _, err := db.Exec(context.Background(), goqdsl.NewQ().Select("uuid").From("bar")); err != nil {
+    t.Fatal(err)
+  }
+}
+
+func TestTenancyPrepared(t *testing.T) {
+  rec := &recorder{}
+  db := Wrap(rec).Tenancy("tenant_id", "foo")
+  p := goqdsl.Prepare(goqdsl.NewQ().Select("uuid").From("foo").Filter(goqdsl.Column[string]("name").EqParam("name")))
+  b, err := p.Bind(map[string]any{"name": "bar"})
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  if _, err := db.Exec(WithTenant(context.Background(), "acme"), b); err != nil {
+    t.Fatal(err)
+  }
+  expected := "SELECT uuid FROM foo WHERE tenant_id = @tenant_id AND   name = @name "
+  if rec.sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, rec.sql)
+  }
+  if args := rec.args[0].(pgx.NamedArgs); args["tenant_id"] != "acme" || args["name"] != "bar" {
+    t.Errorf("unexpected args: %v", args)
+  }
+  if _, err := db.Exec(context.Background(), b); !errors.Is(err, ErrNoTenant) {
+    t.Errorf("expected ErrNoTenant, got %v", err)
+  }
+}
+
+// end
@@ -0,0 +1,59 @@
+package goqdslpgx
+
+import (
+	"context"
+	"fmt"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+// UpsertRows caps the rows in one statement of UpsertAll.
+var UpsertRows = 1000
+
+// UpsertAll inserts rows into table, updating rows that conflict on keyCols,
+// with columns taken from T's model, see goqdsl.ModelOf. Rows are sent in
+// chunks inside one transaction, so either all of them are written or none.
+// It returns the number of rows inserted or updated, or an error without
+// sending anything when T has no columns or one row takes more than
+// MaxParams parameters.
+func UpsertAll[T any](ctx context.Context, db *PgxDB, table string, rows []T, keyCols ...string) (int64, error) {
+
+  if len(rows) == 0 {
+    return 0, nil
+  }
+
+  columns := goqdsl.ModelOf[T]().Columns
+  if len(columns) == 0 {
+    return 0, fmt.Errorf("goqdslpgx: %T has no columns", rows[0])
+  }
+  if len(columns) > MaxParams {
+    return 0, &TooManyParamsError{Params: len(columns), Max: MaxParams}
+  }
+  chunk := max(1, UpsertRows)
+  if n := MaxParams / len(columns); n < chunk {
+    chunk = n
+  }
+
+  var total int64
+  err := db.Tx(ctx, func(tx *PgxDB) error {
+    total = 0
+    for start := 0; start < len(rows); start += chunk {
+      end := min(start+chunk, len(rows))
+
+      q := goqdsl.Upsert(table, columns, keyCols...)
+      for _, row := range rows[start:end] {
+        q.Row(goqdsl.ModelValues(row)...)
+      }
+
+      tag, err := tx.Exec(ctx, q)
+      if err != nil {
+        return err
+      }
+      total += tag.RowsAffected()
+    }
+    return nil
+  })
+  return total, err
+}
+
+// end
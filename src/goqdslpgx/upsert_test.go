@@ -0,0 +1,67 @@
+package goqdslpgx
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func TestUpsertAll(t *testing.T) {
+  defer func(n int) { UpsertRows = n }(UpsertRows)
+  UpsertRows = 2
+
+  rec := &recorder{tag: "INSERT 0 2"}
+  rows := []foo{{"1", "a"}, {"2", "b"}, {"3", "c"}}
+
+  n, err := UpsertAll(context.Background(), Wrap(rec), "foo", rows, "uuid")
+  if err != nil {
+    t.Fatal(err)
+  }
+  if n != 4 {
+    t.Errorf("expected 4 rows affected, got %d", n)
+  }
+  if len(rec.log) != 2 || !rec.committed {
+    t.Fatalf("expected two statements in a committed tx, got %v", rec.log)
+  }
+
+  expected := "INSERT INTO foo (uuid, name) VALUES (@uuid_0, @name_0) ON CONFLICT (uuid) DO UPDATE SET name = EXCLUDED.name"
+  if rec.log[1] != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, rec.log[1])
+  }
+  if args := rec.args[0].(pgx.NamedArgs); args["uuid_0"] != "3" {
+    t.Errorf("unexpected args: %v", args)
+  }
+  if !strings.Contains(rec.log[0], "(@uuid_1, @name_1)") {
+    t.Errorf("expected two rows in the first chunk: %s", rec.log[0])
+  }
+}
+
+func TestUpsertAllEmpty(t *testing.T) {
+  rec := &recorder{}
+  if n, err := UpsertAll[foo](context.Background(), Wrap(rec), "foo", nil, "uuid"); n != 0 || err != nil || len(rec.log) != 0 {
+    t.Errorf("expected nothing to happen, got %d %v %v", n, err, rec.log)
+  }
+}
+
+func TestUpsertAllLimits(t *testing.T) {
+  rec := &recorder{}
+  type empty struct{}
+  if _, err := UpsertAll(context.Background(), Wrap(rec), "foo", []empty{{}}, "uuid"); err == nil {
+    t.Error("expected an error for a model without columns")
+  }
+
+  defer func(n int) { MaxParams = n }(MaxParams)
+  MaxParams = 1
+  var tooMany *TooManyParamsError
+  if _, err := UpsertAll(context.Background(), Wrap(rec), "foo", []foo{{"1", "a"}}, "uuid"); !errors.As(err, &tooMany) {
+    t.Errorf("expected TooManyParamsError, got %v", err)
+  }
+  if len(rec.log) != 0 {
+    t.Errorf("expected nothing sent, got %v", rec.log)
+  }
+}
+
+// end
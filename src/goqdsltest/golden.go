@@ -0,0 +1,97 @@
+package goqdsltest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+var update = flag.Bool("goqdsl.update", false, "rewrite golden SQL files")
+
+// Golden compares the named SQL and arguments of b against
+// testdata/<name>.golden. Run the tests with -goqdsl.update to (re)write the
+// files.
+func Golden(t testing.TB, name string, b goqdsl.Builder) {
+  t.Helper()
+
+  got := Canonical(b)
+  path := filepath.Join("testdata", name+".golden")
+
+  if *update {
+    if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+      t.Fatal(err)
+    }
+    if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+      t.Fatal(err)
+    }
+    return
+  }
+
+  want, err := os.ReadFile(path)
+  if err != nil {
+    t.Fatalf("%v (run with -goqdsl.update to create it)", err)
+  }
+  if diff := lineDiff(string(want), got); diff != "" {
+    t.Errorf("%s differs from golden file:\n%s", name, diff)
+  }
+}
+
+// Canonical renders b as whitespace-normalized SQL followed by its arguments,
+// one per line and sorted by name.
+func Canonical(b goqdsl.Builder) string {
+
+  sql, args := b.BuildNamed()
+
+  keys := make([]string, 0, len(args))
+  for k := range args {
+    keys = append(keys, k)
+  }
+  sort.Strings(keys)
+
+  var sb strings.Builder
+  sb.WriteString(Normalize(sql) + "\n")
+  for _, k := range keys {
+    fmt.Fprintf(&sb, "-- @%s = %#v\n", k, args[k])
+  }
+  return sb.String()
+}
+
+func lineDiff(want, got string) string {
+
+  if want == got {
+    return ""
+  }
+
+  w := strings.Split(want, "\n")
+  g := strings.Split(got, "\n")
+
+  var sb strings.Builder
+  for i := 0; i < len(w) || i < len(g); i++ {
+    var a, b string
+    if i < len(w) {
+      a = w[i]
+    }
+    if i < len(g) {
+      b = g[i]
+    }
+    if a == b {
+      fmt.Fprintf(&sb, "  %s\n", a)
+      continue
+    }
+    if i < len(w) {
+      fmt.Fprintf(&sb, "- %s\n", a)
+    }
+    if i < len(g) {
+      fmt.Fprintf(&sb, "+ %s\n", b)
+    }
+  }
+  return sb.String()
+}
+
+// end
@@ -0,0 +1,25 @@
+package goqdsltest
+
+import (
+	"strings"
+	"testing"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+func TestGolden(t *testing.T) {
+  q := goqdsl.NewQ().Select("uuid", "name").From("foo").Where(map[string]string{"name": "bar"})
+  Golden(t, "select_foo", q)
+}
+
+func TestLineDiff(t *testing.T) {
+  diff := lineDiff("SELECT a\n-- @x = 1\n", "SELECT a\n-- @x = 2\n")
+  if !strings.Contains(diff, "- -- @x = 1") || !strings.Contains(diff, "+ -- @x = 2") {
+    t.Errorf("unexpected diff:\n%s", diff)
+  }
+  if lineDiff("a", "a") != "" {
+    t.Error("expected no diff for equal input")
+  }
+}
+
+// end
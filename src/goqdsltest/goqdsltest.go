@@ -0,0 +1,258 @@
+package goqdsltest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	goqdsl "github.com/raugustinus/goqdsl/src"
+	"github.com/raugustinus/goqdsl/src/goqdslpgx"
+)
+
+// DB is a goqdslpgx.Querier answering statements from registered
+// expectations. Use DB.PgxDB to run code under test against it.
+type DB struct {
+  t testing.TB
+  mu sync.Mutex
+  expectations []*Expectation
+}
+
+type Expectation struct {
+  sql string
+  args map[string]any
+  anyArgs bool
+  columns []string
+  rows [][]any
+  tag string
+  err error
+  met bool
+}
+
+// New returns a mock that fails t at cleanup when expectations are left
+// unmet.
+func New(t testing.TB) *DB {
+  m := &DB{t: t}
+  t.Cleanup(func() {
+    if err := m.ExpectationsWereMet(); err != nil {
+      t.Error(err)
+    }
+  })
+  return m
+}
+
+func (m *DB) PgxDB() *goqdslpgx.PgxDB {
+  return goqdslpgx.Wrap(m)
+}
+
+// Expect matches a statement with the same SQL, ignoring whitespace, and the
+// same named arguments as b.
+func (m *DB) Expect(b goqdsl.Builder) *Expectation {
+  sql, args := b.BuildNamed()
+  if args == nil {
+    args = map[string]any{}
+  }
+  return m.add(&Expectation{sql: Normalize(sql), args: args})
+}
+
+// ExpectSQL matches a statement by SQL alone, ignoring whitespace.
+func (m *DB) ExpectSQL(sql string) *Expectation {
+  return m.add(&Expectation{sql: Normalize(sql), anyArgs: true})
+}
+
+func (m *DB) add(e *Expectation) *Expectation {
+  m.mu.Lock()
+  defer m.mu.Unlock()
+  m.expectations = append(m.expectations, e)
+  return e
+}
+
+// WithArgs narrows an ExpectSQL expectation to these named arguments.
+func (e *Expectation) WithArgs(args map[string]any) *Expectation {
+  e.args, e.anyArgs = args, false
+  return e
+}
+
+// WillReturnRows answers a query with rows, one slice of values per row.
+func (e *Expectation) WillReturnRows(columns []string, rows ...[]any) *Expectation {
+  e.columns, e.rows = columns, rows
+  return e
+}
+
+// WillReturnTag sets the command tag for Exec, e.g. "UPDATE 3".
+func (e *Expectation) WillReturnTag(tag string) *Expectation {
+  e.tag = tag
+  return e
+}
+
+func (e *Expectation) WillReturnError(err error) *Expectation {
+  e.err = err
+  return e
+}
+
+func (m *DB) ExpectationsWereMet() error {
+  m.mu.Lock()
+  defer m.mu.Unlock()
+  var unmet []string
+  for _, e := range m.expectations {
+    if !e.met {
+      unmet = append(unmet, e.sql)
+    }
+  }
+  if len(unmet) > 0 {
+    return fmt.Errorf("goqdsltest: unmet expectations:\n  %s", strings.Join(unmet, "\n  "))
+  }
+  return nil
+}
+
+// Normalize collapses runs of whitespace so formatting differences do not
+// matter when comparing SQL.
+func Normalize(sql string) string {
+  return strings.Join(strings.Fields(sql), " ")
+}
+
+func (m *DB) match(sql string, args []any) (*Expectation, error) {
+
+  named := map[string]any{}
+  if len(args) == 1 {
+    if a, ok := args[0].(pgx.NamedArgs); ok {
+      named = a
+    }
+  }
+
+  m.mu.Lock()
+  defer m.mu.Unlock()
+  sql = Normalize(sql)
+  for _, e := range m.expectations {
+    if e.met || e.sql != sql {
+      continue
+    }
+    if !e.anyArgs && !reflect.DeepEqual(e.args, named) {
+      continue
+    }
+    e.met = true
+    return e, nil
+  }
+  err := fmt.Errorf("goqdsltest: unexpected statement: %s %v", sql, named)
+  m.t.Error(err)
+  return nil, err
+}
+
+func (m *DB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+  e, err := m.match(sql, args)
+  if err != nil {
+    return pgconn.CommandTag{}, err
+  }
+  return pgconn.NewCommandTag(e.tag), e.err
+}
+
+func (m *DB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+  e, err := m.match(sql, args)
+  if err != nil {
+    return nil, err
+  }
+  if e.err != nil {
+    return nil, e.err
+  }
+  return &rows{columns: e.columns, data: e.rows}, nil
+}
+
+func (m *DB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+  r, err := m.Query(ctx, sql, args...)
+  return row{rows: r, err: err}
+}
+
+type row struct {
+  rows pgx.Rows
+  err error
+}
+
+func (r row) Scan(dest ...any) error {
+  if r.err != nil {
+    return r.err
+  }
+  defer r.rows.Close()
+  if !r.rows.Next() {
+    return pgx.ErrNoRows
+  }
+  return r.rows.Scan(dest...)
+}
+
+func (m *DB) Begin(ctx context.Context) (pgx.Tx, error) {
+  return &tx{DB: m}, nil
+}
+
+func (m *DB) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+  return &batchResults{db: m, queued: b.QueuedQueries}
+}
+
+// tx runs on the mock; commits and rollbacks are no-ops.
+type tx struct {
+  *DB
+}
+
+func (t *tx) Begin(ctx context.Context) (pgx.Tx, error) {
+  return t, nil
+}
+
+func (t *tx) Commit(ctx context.Context) error {
+  return nil
+}
+
+func (t *tx) Rollback(ctx context.Context) error {
+  return nil
+}
+
+func (t *tx) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+  return 0, errors.New("goqdsltest: CopyFrom is not supported")
+}
+
+func (t *tx) LargeObjects() pgx.LargeObjects {
+  return pgx.LargeObjects{}
+}
+
+func (t *tx) Prepare(ctx context.Context, name, sql string) (*pgconn.StatementDescription, error) {
+  return nil, errors.New("goqdsltest: Prepare is not supported")
+}
+
+func (t *tx) Conn() *pgx.Conn {
+  return nil
+}
+
+type batchResults struct {
+  db *DB
+  queued []*pgx.QueuedQuery
+  pos int
+}
+
+func (b *batchResults) next() *pgx.QueuedQuery {
+  q := b.queued[b.pos]
+  b.pos++
+  return q
+}
+
+func (b *batchResults) Exec() (pgconn.CommandTag, error) {
+  q := b.next()
+  return b.db.Exec(context.Background(), q.SQL, q.Arguments...)
+}
+
+func (b *batchResults) Query() (pgx.Rows, error) {
+  q := b.next()
+  return b.db.Query(context.Background(), q.SQL, q.Arguments...)
+}
+
+func (b *batchResults) QueryRow() pgx.Row {
+  q := b.next()
+  return b.db.QueryRow(context.Background(), q.SQL, q.Arguments...)
+}
+
+func (b *batchResults) Close() error {
+  return nil
+}
+
+// end
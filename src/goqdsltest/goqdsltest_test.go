@@ -0,0 +1,89 @@
+package goqdsltest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+	"github.com/raugustinus/goqdsl/src/goqdslpgx"
+)
+
+type foo struct {
+  Uuid string
+  Name string
+}
+
+func TestExpectBuilder(t *testing.T) {
+  m := New(t)
+  q := goqdsl.NewQ().Select("uuid", "name").From("foo").Where(map[string]string{"name": "bar"})
+  m.Expect(q).WillReturnRows([]string{"uuid", "name"}, []any{"1", "bar"}, []any{"2", "bar"})
+
+  values, err := goqdslpgx.FetchAll[foo](context.Background(), m.PgxDB(), q)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(values) != 2 || values[1] != (foo{Uuid: "2", Name: "bar"}) {
+    t.Errorf("unexpected values: %v", values)
+  }
+}
+
+func TestExpectSQL(t *testing.T) {
+  m := New(t)
+  m.ExpectSQL("SELECT   uuid, name\n FROM foo").WillReturnRows([]string{"uuid", "name"}, []any{"1", "bar"})
+
+  q := goqdsl.NewQ().Select("uuid", "name").From("foo")
+  value, err := goqdslpgx.FetchOne[foo](context.Background(), m.PgxDB(), q)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if value.Name != "bar" {
+    t.Errorf("unexpected value: %v", value)
+  }
+}
+
+func TestExpectArgsMismatch(t *testing.T) {
+  m := New(&testing.T{})
+  m.Expect(goqdsl.NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "bar"}))
+
+  q := goqdsl.NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "baz"})
+  if _, err := m.PgxDB().Exec(context.Background(), q); err == nil {
+    t.Error("expected an error for unexpected args")
+  }
+  if err := m.ExpectationsWereMet(); err == nil {
+    t.Error("expected unmet expectations")
+  }
+}
+
+func TestExecTagAndError(t *testing.T) {
+  m := New(t)
+  q := goqdsl.NewQ().Select("1").From("foo")
+  boom := errors.New("boom")
+  m.Expect(q).WillReturnTag("SELECT 1")
+  m.Expect(q).WillReturnError(boom)
+
+  db := m.PgxDB()
+  tag, err := db.Exec(context.Background(), q)
+  if err != nil || tag.RowsAffected() != 1 {
+    t.Errorf("unexpected result: %v %v", tag, err)
+  }
+  if _, err := db.Exec(context.Background(), q); !errors.Is(err, boom) {
+    t.Errorf("expected boom, got %v", err)
+  }
+}
+
+func TestTx(t *testing.T) {
+  m := New(t)
+  q := goqdsl.NewQ().Select("uuid").From("foo")
+  m.Expect(q).WillReturnRows([]string{"uuid"}, []any{"1"})
+
+  err := m.PgxDB().Tx(context.Background(), func(tx *goqdslpgx.PgxDB) error {
+    _, err := goqdslpgx.FetchColumn[string](context.Background(), tx, q)
+    return err
+  })
+  if err != nil {
+    t.Fatal(err)
+  }
+}
+
+// end
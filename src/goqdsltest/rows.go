@@ -0,0 +1,121 @@
+package goqdsltest
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// rows serves canned values as pgx.Rows. Scan assigns values by reflection,
+// converting between compatible types, so fixtures can use plain Go values.
+type rows struct {
+  columns []string
+  data [][]any
+  pos int
+  closed bool
+  err error
+}
+
+func (r *rows) Close() {
+  r.closed = true
+}
+
+func (r *rows) Err() error {
+  return r.err
+}
+
+func (r *rows) CommandTag() pgconn.CommandTag {
+  return pgconn.NewCommandTag("SELECT " + strconv.Itoa(len(r.data)))
+}
+
+func (r *rows) FieldDescriptions() []pgconn.FieldDescription {
+  fields := make([]pgconn.FieldDescription, len(r.columns))
+  for i, c := range r.columns {
+    fields[i].Name = c
+  }
+  return fields
+}
+
+func (r *rows) Next() bool {
+  if r.closed || r.err != nil || r.pos >= len(r.data) {
+    r.closed = true
+    return false
+  }
+  r.pos++
+  return true
+}
+
+func (r *rows) Scan(dest ...any) error {
+
+  if len(dest) == 1 {
+    if scanner, ok := dest[0].(pgx.RowScanner); ok {
+      return scanner.ScanRow(r)
+    }
+  }
+
+  row := r.data[r.pos-1]
+  if len(dest) != len(row) {
+    return fmt.Errorf("goqdsltest: %d destinations for %d columns", len(dest), len(row))
+  }
+  for i, d := range dest {
+    if d == nil {
+      continue
+    }
+    if err := assign(d, row[i]); err != nil {
+      return fmt.Errorf("goqdsltest: column %s: %w", r.columns[i], err)
+    }
+  }
+  return nil
+}
+
+func assign(dest any, value any) error {
+
+  dv := reflect.ValueOf(dest)
+  if dv.Kind() != reflect.Pointer || dv.IsNil() {
+    return fmt.Errorf("destination %T is not a pointer", dest)
+  }
+  dv = dv.Elem()
+
+  if value == nil {
+    switch dv.Kind() {
+    case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+      dv.Set(reflect.Zero(dv.Type()))
+      return nil
+    }
+    return fmt.Errorf("cannot scan NULL into %s", dv.Type())
+  }
+
+  vv := reflect.ValueOf(value)
+  switch {
+  case vv.Type().AssignableTo(dv.Type()):
+    dv.Set(vv)
+  case dv.Kind() == reflect.Pointer:
+    p := reflect.New(dv.Type().Elem())
+    if err := assign(p.Interface(), value); err != nil {
+      return err
+    }
+    dv.Set(p)
+  case vv.Type().ConvertibleTo(dv.Type()) && (dv.Kind() == reflect.String) == (vv.Kind() == reflect.String):
+    dv.Set(vv.Convert(dv.Type()))
+  default:
+    return fmt.Errorf("cannot scan %T into %s", value, dv.Type())
+  }
+  return nil
+}
+
+func (r *rows) Values() ([]any, error) {
+  return r.data[r.pos-1], nil
+}
+
+func (r *rows) RawValues() [][]byte {
+  return nil
+}
+
+func (r *rows) Conn() *pgx.Conn {
+  return nil
+}
+
+// end
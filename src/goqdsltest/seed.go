@@ -0,0 +1,119 @@
+package goqdsltest
+
+import (
+	"context"
+	"fmt"
+
+	goqdsl "github.com/raugustinus/goqdsl/src"
+	"github.com/raugustinus/goqdsl/src/goqdslpgx"
+)
+
+// Seed holds fixtures, slices of db-tagged structs, to load into a database
+// table by table, each after the tables it depends on:
+//
+//  seed := goqdsltest.Add(goqdsltest.Add(&goqdsltest.Seed{}, users), orders, "users")
+//  err := seed.Load(ctx, db)
+type Seed struct {
+  tables []seedTable
+}
+
+type seedTable struct {
+  name string
+  deps []string
+  insert func(ctx context.Context, db *goqdslpgx.PgxDB) error
+}
+
+// Add adds rows for the table of T's model, see goqdsl.ModelOf, to be
+// inserted after the rows for dependsOn. Dependencies on tables outside s are
+// ignored.
+func Add[T any](s *Seed, rows []T, dependsOn ...string) *Seed {
+  table := goqdsl.ModelOf[T]().Table
+  s.tables = append(s.tables, seedTable{
+    name: table,
+    deps: dependsOn,
+    insert: func(ctx context.Context, db *goqdslpgx.PgxDB) error {
+      _, err := goqdslpgx.UpsertAll(ctx, db, table, rows)
+      return err
+    },
+  })
+  return s
+}
+
+// Load inserts the fixtures in dependency order in one transaction. Cyclic
+// dependencies fail before anything is inserted.
+func (s *Seed) Load(ctx context.Context, db *goqdslpgx.PgxDB) error {
+  tables, err := s.order()
+  if err != nil {
+    return err
+  }
+  return db.Tx(ctx, func(tx *goqdslpgx.PgxDB) error {
+    for _, t := range tables {
+      if err := t.insert(ctx, tx); err != nil {
+        return fmt.Errorf("goqdsltest: seed %s: %w", t.name, err)
+      }
+    }
+    return nil
+  })
+}
+
+// Reset empties the tables of s, and those referencing them, and restarts
+// their sequences, e.g. between test suites.
+func (s *Seed) Reset(ctx context.Context, db *goqdslpgx.PgxDB) error {
+  var names []string
+  seen := map[string]bool{}
+  for _, t := range s.tables {
+    if !seen[t.name] {
+      seen[t.name] = true
+      names = append(names, t.name)
+    }
+  }
+  if len(names) == 0 {
+    return nil
+  }
+  _, err := db.Exec(ctx, goqdsl.Truncate(names...).RestartIdentity().Cascade())
+  return err
+}
+
+// order sorts the tables after their dependencies, otherwise keeping the
+// order they were added in.
+func (s *Seed) order() ([]seedTable, error) {
+
+  byName := map[string][]int{}
+  for i, t := range s.tables {
+    byName[t.name] = append(byName[t.name], i)
+  }
+
+  const visiting, done = 1, 2
+  state := make([]int, len(s.tables))
+  var sorted []seedTable
+
+  var visit func(i int) error
+  visit = func(i int) error {
+    switch state[i] {
+    case visiting:
+      return fmt.Errorf("goqdsltest: seed dependency cycle through %s", s.tables[i].name)
+    case done:
+      return nil
+    }
+    state[i] = visiting
+    for _, dep := range s.tables[i].deps {
+      for _, j := range byName[dep] {
+        if err := visit(j); err != nil {
+          return err
+        }
+      }
+    }
+    state[i] = done
+    sorted = append(sorted, s.tables[i])
+    return nil
+  }
+
+  for i := range s.tables {
+    if err := visit(i); err != nil {
+      return nil, err
+    }
+  }
+  return sorted, nil
+}
+
+// end
@@ -0,0 +1,64 @@
+package goqdsltest
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/raugustinus/goqdsl/src/goqdslpgx"
+)
+
+type seedUser struct {
+  Uuid string `db:"uuid,pk"`
+  Name string `db:"name"`
+}
+
+func (seedUser) TableName() string { return "users" }
+
+type seedOrder struct {
+  Uuid string `db:"uuid,pk"`
+  UserUuid string `db:"user_uuid"`
+}
+
+func (seedOrder) TableName() string { return "orders" }
+
+func TestSeedLoad(t *testing.T) {
+  m := New(t)
+  m.ExpectSQL("INSERT INTO users (uuid, name) VALUES (@uuid_0, @name_0)").WithArgs(map[string]any{"uuid_0": "u1", "name_0": "bar"}).WillReturnTag("INSERT 0 1")
+  m.ExpectSQL("INSERT INTO orders (uuid, user_uuid) VALUES (@uuid_0, @user_uuid_0), (@uuid_1, @user_uuid_1)").WillReturnTag("INSERT 0 2")
+
+  var tables []string
+  db := m.PgxDB().Use(func(ctx context.Context, call *goqdslpgx.Call, next goqdslpgx.Next) (goqdslpgx.Result, error) {
+    tables = append(tables, strings.Fields(call.SQL)[2])
+    return next(ctx, call)
+  })
+
+  seed := Add(&Seed{}, []seedOrder{{"o1", "u1"}, {"o2", "u1"}}, "users")
+  seed = Add(seed, []seedUser{{"u1", "bar"}})
+  if err := seed.Load(context.Background(), db); err != nil {
+    t.Fatal(err)
+  }
+  if !reflect.DeepEqual(tables, []string{"users", "orders"}) {
+    t.Errorf("expected users before orders, got %v", tables)
+  }
+}
+
+func TestSeedCycle(t *testing.T) {
+  seed := Add(Add(&Seed{}, []seedOrder{{"o1", "u1"}}, "users"), []seedUser{{"u1", "bar"}}, "orders")
+  if err := seed.Load(context.Background(), New(t).PgxDB()); err == nil || !strings.Contains(err.Error(), "cycle") {
+    t.Errorf("expected a cycle error, got %v", err)
+  }
+}
+
+func TestSeedReset(t *testing.T) {
+  m := New(t)
+  m.ExpectSQL("TRUNCATE orders, users RESTART IDENTITY CASCADE")
+
+  seed := Add(Add(Add(&Seed{}, []seedOrder{{"o1", "u1"}}, "users"), []seedUser{{"u1", "bar"}}), []seedOrder{{"o2", "u1"}})
+  if err := seed.Reset(context.Background(), m.PgxDB()); err != nil {
+    t.Fatal(err)
+  }
+}
+
+// end
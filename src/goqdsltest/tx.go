@@ -0,0 +1,28 @@
+package goqdsltest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/raugustinus/goqdsl/src/goqdslpgx"
+)
+
+// Tx returns a PgxDB running in a transaction on db that is rolled back when
+// the test ends, so tests can share one database without seeing each other's
+// writes. When db is itself in a transaction a savepoint is used.
+func Tx(t testing.TB, db *goqdslpgx.PgxDB) *goqdslpgx.PgxDB {
+  t.Helper()
+
+  txdb, tx, err := db.Begin(context.Background())
+  if err != nil {
+    t.Fatalf("goqdsltest: begin: %v", err)
+  }
+  t.Cleanup(func() {
+    if err := tx.Rollback(context.Background()); err != nil {
+      t.Errorf("goqdsltest: rollback: %v", err)
+    }
+  })
+  return txdb
+}
+
+// end
@@ -0,0 +1,51 @@
+package goqdsltest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	goqdsl "github.com/raugustinus/goqdsl/src"
+	"github.com/raugustinus/goqdsl/src/goqdslpgx"
+)
+
+type rollbackTx struct {
+  *tx
+  rolledBack *bool
+}
+
+func (t rollbackTx) Rollback(ctx context.Context) error {
+  *t.rolledBack = true
+  return nil
+}
+
+type rollbackDB struct {
+  *DB
+  rolledBack bool
+}
+
+func (m *rollbackDB) Begin(ctx context.Context) (pgx.Tx, error) {
+  return rollbackTx{&tx{DB: m.DB}, &m.rolledBack}, nil
+}
+
+func TestTxRollsBack(t *testing.T) {
+  m := &rollbackDB{DB: New(t)}
+  q := goqdsl.NewQ().Select("uuid").From("foo")
+  m.Expect(q)
+
+  t.Run("test", func(t *testing.T) {
+    db := Tx(t, goqdslpgx.Wrap(m))
+    if _, err := db.Exec(context.Background(), q); err != nil {
+      t.Fatal(err)
+    }
+    if m.rolledBack {
+      t.Error("rolled back before the test ended")
+    }
+  })
+
+  if !m.rolledBack {
+    t.Error("expected a rollback when the test ended")
+  }
+}
+
+// end
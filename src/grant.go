@@ -0,0 +1,98 @@
+package goqdsl
+
+import (
+	"strings"
+)
+
+type GrantQ struct {
+  revoke bool
+  privileges []string
+  on string
+  roles []string
+  withGrantOption bool
+  cascade bool
+}
+
+func Grant(privileges ...string) *GrantQ {
+  return &GrantQ{privileges: privileges}
+}
+
+func Revoke(privileges ...string) *GrantQ {
+  return &GrantQ{revoke: true, privileges: privileges}
+}
+
+func (g *GrantQ) OnTable(tables ...string) *GrantQ {
+  g.on = "TABLE " + strings.Join(tables, ", ")
+  return g
+}
+
+func (g *GrantQ) OnAllTablesInSchema(schemas ...string) *GrantQ {
+  g.on = "ALL TABLES IN SCHEMA " + strings.Join(schemas, ", ")
+  return g
+}
+
+func (g *GrantQ) OnAllSequencesInSchema(schemas ...string) *GrantQ {
+  g.on = "ALL SEQUENCES IN SCHEMA " + strings.Join(schemas, ", ")
+  return g
+}
+
+func (g *GrantQ) OnSchema(schemas ...string) *GrantQ {
+  g.on = "SCHEMA " + strings.Join(schemas, ", ")
+  return g
+}
+
+func (g *GrantQ) To(roles ...string) *GrantQ {
+  g.roles = roles
+  return g
+}
+
+// From is To for revokes, it reads better.
+func (g *GrantQ) From(roles ...string) *GrantQ {
+  return g.To(roles...)
+}
+
+func (g *GrantQ) WithGrantOption() *GrantQ {
+  g.withGrantOption = true
+  return g
+}
+
+func (g *GrantQ) Cascade() *GrantQ {
+  g.cascade = true
+  return g
+}
+
+func (g *GrantQ) BuildNamed() (string, map[string]any) {
+  return g.Query(), nil
+}
+
+func (g *GrantQ) BuildPositional() (string, []any) {
+  return g.Query(), nil
+}
+
+func (g *GrantQ) Query() string {
+
+  privileges := "ALL"
+  if len(g.privileges) > 0 {
+    privileges = strings.Join(g.privileges, ", ")
+  }
+
+  if g.revoke {
+    sql := "REVOKE "
+    if g.withGrantOption {
+      sql += "GRANT OPTION FOR "
+    }
+    sql += privileges + " ON " + g.on + " FROM " + strings.Join(g.roles, ", ")
+    if g.cascade {
+      sql += " CASCADE"
+    }
+    return sql
+  }
+
+  sql := "GRANT " + privileges + " ON " + g.on + " TO " + strings.Join(g.roles, ", ")
+  if g.withGrantOption {
+    sql += " WITH GRANT OPTION"
+  }
+  return sql
+}
+
+// end
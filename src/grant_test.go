@@ -0,0 +1,25 @@
+package goqdsl
+
+import (
+	"testing"
+)
+
+func TestGrant(t *testing.T) {
+  tests := []struct {
+    q Statement
+    expected string
+  }{
+    {Grant("SELECT", "INSERT").OnTable("foo", "bar").To("svc_foo"), "GRANT SELECT, INSERT ON TABLE foo, bar TO svc_foo"},
+    {Grant("USAGE").OnSchema("reporting").To("svc_a", "svc_b").WithGrantOption(), "GRANT USAGE ON SCHEMA reporting TO svc_a, svc_b WITH GRANT OPTION"},
+    {Grant().OnAllTablesInSchema("public").To("admin"), "GRANT ALL ON ALL TABLES IN SCHEMA public TO admin"},
+    {Revoke("DELETE").OnTable("foo").From("svc_foo"), "REVOKE DELETE ON TABLE foo FROM svc_foo"},
+    {Revoke().OnSchema("reporting").From("svc_a").Cascade(), "REVOKE ALL ON SCHEMA reporting FROM svc_a CASCADE"},
+    {Revoke("SELECT").OnTable("foo").From("svc_a").WithGrantOption(), "REVOKE GRANT OPTION FOR SELECT ON TABLE foo FROM svc_a"},
+  }
+
+  for _, test := range tests {
+    if sql := test.q.Query(); sql != test.expected {
+      t.Errorf("expected:\n%s\ngot:\n%s", test.expected, sql)
+    }
+  }
+}
@@ -0,0 +1,147 @@
+package goqdsl
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// whereSuffixes maps the operator suffixes of a where input to filter tag
+// operators, see Filters. Longer suffixes come first, so _not_in is not read
+// as _in.
+var whereSuffixes = []struct{ suffix, op string }{
+  {"_starts_with", "starts_with"}, {"_contains", "contains"}, {"_not_in", "not_in"},
+  {"_ilike", "ilike"}, {"_like", "like"}, {"_not", "ne"}, {"_gte", "gte"}, {"_lte", "lte"},
+  {"_gt", "gt"}, {"_lt", "lt"}, {"_in", "in"}, {"_eq", "eq"},
+}
+
+// ParseWhereInput translates a GraphQL style where input, as decoded from
+// JSON, into a condition:
+//
+//  {"OR": [{"name_contains": "bar"}, {"age_gte": 30}], "NOT": {"kind_in": ["a", "b"]}, "deleted_at": null}
+//
+// Keys are AND and OR over a list of inputs, NOT over one, or a field of
+// fields with an optional suffix: _eq, _not, _lt, _lte, _gt, _gte, _in,
+// _not_in, _like, _ilike, _contains or _starts_with. fields maps the field
+// names to columns; other fields fail. A null value with no suffix or _not
+// tests for NULL. The entries of one input are ANDed.
+func ParseWhereInput(input map[string]any, fields map[string]string) (Cond, error) {
+  return whereInput(input, fields, "")
+}
+
+func whereInput(input map[string]any, fields map[string]string, path string) (Cond, error) {
+
+  keys := make([]string, 0, len(input))
+  for k := range input {
+    keys = append(keys, k)
+  }
+  sort.Strings(keys)
+
+  var conds []Cond
+  for _, k := range keys {
+    c, err := whereEntry(k, input[k], fields, path+k)
+    if err != nil {
+      return Cond{}, err
+    }
+    conds = append(conds, c)
+  }
+  if len(conds) == 1 {
+    return conds[0], nil
+  }
+  return And(conds...), nil
+}
+
+func whereEntry(key string, value any, fields map[string]string, path string) (Cond, error) {
+
+  switch key {
+  case "AND", "OR":
+    list, ok := value.([]any)
+    if !ok {
+      return Cond{}, fmt.Errorf("goqdsl: where input %s: expected a list", path)
+    }
+    conds := make([]Cond, len(list))
+    for i, item := range list {
+      m, ok := item.(map[string]any)
+      if !ok {
+        return Cond{}, fmt.Errorf("goqdsl: where input %s.%d: expected an object", path, i)
+      }
+      c, err := whereInput(m, fields, fmt.Sprintf("%s.%d.", path, i))
+      if err != nil {
+        return Cond{}, err
+      }
+      conds[i] = c
+    }
+    if key == "AND" {
+      return And(conds...), nil
+    }
+    return Or(conds...), nil
+
+  case "NOT":
+    m, ok := value.(map[string]any)
+    if !ok {
+      return Cond{}, fmt.Errorf("goqdsl: where input %s: expected an object", path)
+    }
+    c, err := whereInput(m, fields, path+".")
+    return Not(c), err
+  }
+
+  field, op := key, "eq"
+  if _, ok := fields[key]; !ok {
+    for _, s := range whereSuffixes {
+      if strings.HasSuffix(key, s.suffix) {
+        field, op = strings.TrimSuffix(key, s.suffix), s.op
+        break
+      }
+    }
+  }
+  column, ok := fields[field]
+  if !ok {
+    return Cond{}, fmt.Errorf("goqdsl: where input %s: unknown field %s", path, field)
+  }
+
+  switch op {
+  case "eq", "ne":
+    if value == nil {
+      if op == "eq" {
+        return Cond{column: column, op: "IS NULL"}, nil
+      }
+      return Cond{column: column, op: "IS NOT NULL"}, nil
+    }
+  case "in", "not_in":
+    rv := reflect.ValueOf(value)
+    if value == nil || rv.Kind() != reflect.Slice {
+      return Cond{}, fmt.Errorf("goqdsl: where input %s: expected a list", path)
+    }
+    values := make([]any, rv.Len())
+    for i := range values {
+      values[i] = rv.Index(i).Interface()
+    }
+    if op == "not_in" {
+      return Not(Cond{column: column, op: "IN", values: values}), nil
+    }
+    return Cond{column: column, op: "IN", values: values}, nil
+  case "contains", "starts_with":
+    s, ok := value.(string)
+    if !ok {
+      return Cond{}, fmt.Errorf("goqdsl: where input %s: expected a string", path)
+    }
+    pattern := escapeLike(s) + "%"
+    if op == "contains" {
+      pattern = "%" + pattern
+    }
+    return Cond{column: column, op: "LIKE", values: []any{pattern}}, nil
+  }
+  if value == nil {
+    return Cond{}, fmt.Errorf("goqdsl: where input %s: null value", path)
+  }
+  return Cond{column: column, op: filterOps[op], values: []any{value}}, nil
+}
+
+// escapeLike escapes the LIKE wildcards in s with a backslash, the default
+// escape character.
+func escapeLike(s string) string {
+  return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// end
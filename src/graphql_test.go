@@ -0,0 +1,74 @@
+package goqdsl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+var whereFields = map[string]string{"name": "f.name", "age": "f.age", "kind": "f.kind", "deleted_at": "f.deleted_at"}
+
+func TestParseWhereInput(t *testing.T) {
+  var input map[string]any
+  err := json.Unmarshal([]byte(`{
+    "OR": [{"name_contains": "50%"}, {"age_gte": 30, "age_lt": 40}],
+    "NOT": {"kind_in": ["a", "b"]},
+    "deleted_at": null
+  }`), &input)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  c, err := ParseWhereInput(input, whereFields)
+  if err != nil {
+    t.Fatal(err)
+  }
+  sql, args := NewQ().Select("f.uuid").From("foo f").Filter(c).BuildNamed()
+  expected := "SELECT f.uuid FROM foo f WHERE (NOT (f.kind IN (@f_kind, @f_kind_2)) AND (f.name LIKE @f_name OR (f.age >= @f_age AND f.age < @f_age_2)) AND f.deleted_at IS NULL) "
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+  if args["f_name"] != `%50\%%` || args["f_age"] != 30.0 || args["f_kind_2"] != "b" {
+    t.Errorf("unexpected args: %v", args)
+  }
+}
+
+func TestParseWhereInputErrors(t *testing.T) {
+  for _, input := range []string{
+    `{"secret": 1}`,
+    `{"name_sounds_like": "bar"}`,
+    `{"AND": {"name": "bar"}}`,
+    `{"OR": [{"name": "bar"}, {"secret_gt": 1}]}`,
+    `{"kind_in": "a"}`,
+    `{"age_gt": null}`,
+  } {
+    var m map[string]any
+    if err := json.Unmarshal([]byte(input), &m); err != nil {
+      t.Fatal(err)
+    }
+    if _, err := ParseWhereInput(m, whereFields); err == nil {
+      t.Errorf("%s: expected an error", input)
+    }
+  }
+}
+
+func TestJSONCondTree(t *testing.T) {
+  c := Or(Column[string]("name").Eq("bar"), Not(And(Cond{column: "deleted_at", op: "IS NULL"})))
+  q := NewQ().Select("uuid").From("foo").Filter(c)
+
+  b, err := json.Marshal(q)
+  if err != nil {
+    t.Fatal(err)
+  }
+  loaded := NewQ()
+  if err := json.Unmarshal(b, loaded); err != nil {
+    t.Fatal(err)
+  }
+  if loaded.Query() != q.Query() {
+    t.Errorf("expected %s, got %s", q.Query(), loaded.Query())
+  }
+  if err := json.Unmarshal([]byte(`{"select":["uuid"],"from":"foo","filter":[{"op":"NOT"}]}`), NewQ()); err == nil {
+    t.Error("expected an error for NOT without a condition")
+  }
+}
+
+// end
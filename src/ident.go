@@ -0,0 +1,220 @@
+package goqdsl
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+  identPattern = `(?:[A-Za-z_][A-Za-z0-9_$]*|"(?:[^"]|"")+")`
+  qualifiedPattern = identPattern + `(?:\.` + identPattern + `)*`
+  columnPattern = `(?:\*|` + qualifiedPattern + `(?:\.\*)?)`
+)
+
+var (
+  qualifiedIdent = regexp.MustCompile(`^` + qualifiedPattern + `$`)
+  castType = regexp.MustCompile(`^` + qualifiedPattern + `(?:\[\])?$`)
+  tableRef = regexp.MustCompile(`^` + qualifiedPattern + `(?:\s+(?:(?i:AS)\s+)?` + identPattern + `)?$`)
+  selectItem = regexp.MustCompile(`^(?:` + columnPattern + `|` + identPattern +
+    `\(\s*(?:\*|` + columnPattern + `(?:\s*,\s*` + columnPattern + `)*)?\s*\))` +
+    `(?:\s+(?:(?i:AS)\s+)?` + identPattern + `)?$`)
+)
+
+// IdentifierError reports a table, column or select item outside the safe
+// grammar.
+type IdentifierError struct {
+  Kind string
+  Identifier string
+}
+
+func (e *IdentifierError) Error() string {
+  return "goqdsl: unsafe " + e.Kind + " " + strconv.Quote(e.Identifier)
+}
+
+// Expr is SQL trusted as written, for the select list and GROUP BY of a Q,
+// see Q.SelectExpr. It comes from Unsafe or from the helpers here, such as
+// JSONAgg, whose expressions are only trusted when their columns pass
+// CheckIdentifiers.
+type Expr struct {
+  sql string
+  err error
+}
+
+// Unsafe trusts expr as SQL, e.g. SelectExpr(Unsafe("now() - created AS
+// age")). Never pass user input.
+func Unsafe(expr string) Expr {
+  return Expr{sql: expr}
+}
+
+func (e Expr) String() string {
+  return e.sql
+}
+
+// Ident quotes each part as an identifier and joins them with dots, e.g.
+// Ident("legacy", "Order Lines") gives "legacy"."Order Lines". The result can
+// be used wherever a table or column name is taken.
+func Ident(parts ...string) string {
+  var sb strings.Builder
+  for i, p := range parts {
+    if i > 0 {
+      sb.WriteByte('.')
+    }
+    sb.WriteByte('"')
+    sb.WriteString(strings.ReplaceAll(p, `"`, `""`))
+    sb.WriteByte('"')
+  }
+  return sb.String()
+}
+
+// CheckIdentifiers verifies that the table and column names in b are plain or
+// quoted, possibly schema qualified, identifiers, and select items columns,
+// * or function calls over them with an optional alias. Anything else must be
+// added to a Q as an Expr. Builders without identifiers to check pass.
+func CheckIdentifiers(b Builder) error {
+  if c, ok := b.(interface{ checkIdentifiers() error }); ok {
+    return c.checkIdentifiers()
+  }
+  return nil
+}
+
+func checkIdent(kind string, re *regexp.Regexp, idents ...string) error {
+  for _, id := range idents {
+    if !re.MatchString(id) {
+      return &IdentifierError{Kind: kind, Identifier: id}
+    }
+  }
+  return nil
+}
+
+// checkItems checks items like checkIdent, except for those q took as an
+// Expr, which carry their own result.
+func (q *Q) checkItems(kind string, re *regexp.Regexp, items []string) error {
+  for _, item := range items {
+    if err, ok := q.exprs[item]; ok {
+      if err != nil {
+        return err
+      }
+      continue
+    }
+    if err := checkIdent(kind, re, item); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+
+func (q *Q) checkIdentifiers() error {
+  if err := q.checkItems("select item", selectItem, q.fields); err != nil {
+    return err
+  }
+  if err := checkIdent("table", qualifiedIdent, q.from); err != nil {
+    return err
+  }
+  if q.alias != "" {
+    if err := checkIdent("alias", qualifiedIdent, q.alias); err != nil {
+      return err
+    }
+  }
+  for _, j := range q.joins {
+    if err := checkIdent("table", tableRef, j.table); err != nil {
+      return err
+    }
+    if err := checkIdent("column", qualifiedIdent, j.left, j.right); err != nil {
+      return err
+    }
+  }
+  if q.into != "" {
+    if err := checkIdent("table", qualifiedIdent, q.into); err != nil {
+      return err
+    }
+  }
+  for k := range q.criteria {
+    if err := checkIdent("column", qualifiedIdent, k); err != nil {
+      return err
+    }
+  }
+  for _, n := range q.nulls {
+    if err := checkIdent("column", qualifiedIdent, n.column); err != nil {
+      return err
+    }
+  }
+  for _, c := range q.conds {
+    if err := c.check(); err != nil {
+      return err
+    }
+    err := c.columns(func(column string) error {
+      return checkIdent("column", qualifiedIdent, column)
+    })
+    if err != nil {
+      return err
+    }
+  }
+  if err := q.checkItems("column", qualifiedIdent, q.groupBy); err != nil {
+    return err
+  }
+  for _, o := range q.order {
+    if err := checkIdent("column", qualifiedIdent, o.column); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+
+func (u *UpsertQ) checkIdentifiers() error {
+  if err := checkIdent("table", qualifiedIdent, u.table); err != nil {
+    return err
+  }
+  if err := checkIdent("column", qualifiedIdent, u.columns...); err != nil {
+    return err
+  }
+  return checkIdent("column", qualifiedIdent, u.keys...)
+}
+
+func (c *CreateTableQ) checkIdentifiers() error {
+  if err := checkIdent("table", qualifiedIdent, c.table); err != nil {
+    return err
+  }
+  for _, col := range c.columns {
+    if err := checkIdent("column", qualifiedIdent, col.name); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+
+func (c *CreateTableAsQ) checkIdentifiers() error {
+  if err := checkIdent("table", qualifiedIdent, c.table); err != nil {
+    return err
+  }
+  return c.query.checkIdentifiers()
+}
+
+func (m *MaterializedViewQ) checkIdentifiers() error {
+  if err := checkIdent("table", qualifiedIdent, m.name); err != nil {
+    return err
+  }
+  return m.query.checkIdentifiers()
+}
+
+func (r *RefreshQ) checkIdentifiers() error {
+  return checkIdent("table", qualifiedIdent, r.name)
+}
+
+func (t *TruncateQ) checkIdentifiers() error {
+  return checkIdent("table", qualifiedIdent, t.tables...)
+}
+
+func (p *PartitionQ) checkIdentifiers() error {
+  return checkIdent("table", qualifiedIdent, p.name, p.parent)
+}
+
+func (d *DetachPartitionQ) checkIdentifiers() error {
+  return checkIdent("table", qualifiedIdent, d.parent, d.name)
+}
+
+func (d *DropTableQ) checkIdentifiers() error {
+  return checkIdent("table", qualifiedIdent, d.tables...)
+}
+
+// end
@@ -0,0 +1,75 @@
+package goqdsl
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckIdentifiers(t *testing.T) {
+  for _, item := range []string{"uuid", "foo.uuid", "*", "foo.*", `"Weird ""name"""`, "COUNT(*)", "lower(name) AS lname", "max(foo.created) latest", "public.foo.id"} {
+    if err := CheckIdentifiers(NewQ().Select(item).From("public.foo")); err != nil {
+      t.Errorf("%s: unexpected error %v", item, err)
+    }
+  }
+
+  for _, q := range []*Q{
+    NewQ().Select("uuid; DROP TABLE foo").From("foo"),
+    NewQ().Select("uuid").From("foo f JOIN bar b ON true"),
+    NewQ().Select("uuid").From("foo").Where(map[string]string{"1=1 OR name": "x"}),
+    NewQ().Select("now() - created").From("foo"),
+  } {
+    var identErr *IdentifierError
+    if err := CheckIdentifiers(q); !errors.As(err, &identErr) {
+      t.Errorf("%s: expected an IdentifierError, got %v", q.Query(), err)
+    }
+  }
+}
+
+func TestUnsafe(t *testing.T) {
+  q := NewQ().Select("uuid").SelectExpr(Unsafe("now() - created AS age")).From("foo")
+  if err := CheckIdentifiers(q); err != nil {
+    t.Errorf("expected Unsafe to pass, got %v", err)
+  }
+  if err := CheckIdentifiers(NewQ().Select("now() - created AS age").From("foo")); err == nil {
+    t.Error("expected Unsafe to trust only its own query")
+  }
+}
+
+func TestCheckIdentifiersBuilders(t *testing.T) {
+  for _, b := range []Builder{
+    Upsert("foo", []string{"uuid", "name);--"}, "uuid"),
+    CreateTable("foo bar").Column("uuid", "uuid"),
+    CreateMaterializedView("v", NewQ().Select("a").From("x y")),
+    RefreshMaterializedView("v; DROP"),
+    Truncate("foo", "bar; DROP TABLE baz"),
+    DropTable("foo bar"),
+  } {
+    if err := CheckIdentifiers(b); err == nil {
+      t.Errorf("%s: expected an error", b.Query())
+    }
+  }
+  if err := CheckIdentifiers(Notify("c", "p")); err != nil {
+    t.Errorf("expected builders without identifiers to pass, got %v", err)
+  }
+}
+
+
+func TestIdent(t *testing.T) {
+  if id := Ident("legacy", `Order "Lines"`); id != `"legacy"."Order ""Lines"""` {
+    t.Errorf("unexpected ident: %s", id)
+  }
+
+  q := NewQ().Select(Ident("weird column")).From(Ident("legacy", "Order Lines")).Where(map[string]string{Ident("Status"): "open"})
+  sql, args := q.BuildNamed()
+  if sql != `SELECT "weird column" FROM "legacy"."Order Lines" WHERE "Status" = @_Status_ ` {
+    t.Errorf("unexpected sql: %s", sql)
+  }
+  if args["_Status_"] != "open" {
+    t.Errorf("unexpected args: %v", args)
+  }
+  if err := CheckIdentifiers(q); err != nil {
+    t.Errorf("expected quoted identifiers to pass, got %v", err)
+  }
+}
+
+// end
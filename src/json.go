@@ -0,0 +1,154 @@
+package goqdsl
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type jsonJoin struct {
+  Table string `json:"table"`
+  Left string `json:"left"`
+  Right string `json:"right"`
+}
+
+type jsonCond struct {
+  Column string `json:"column,omitempty"`
+  Op string `json:"op"`
+  Values []any `json:"values,omitempty"`
+  Zone string `json:"zone,omitempty"`
+  Cast string `json:"cast,omitempty"`
+  Conds []jsonCond `json:"conds,omitempty"`
+}
+
+type jsonOrder struct {
+  Column string `json:"column"`
+  Desc bool `json:"desc,omitempty"`
+}
+
+type jsonQ struct {
+  Select []string `json:"select"`
+  From string `json:"from"`
+  Alias string `json:"alias,omitempty"`
+  Into string `json:"into,omitempty"`
+  Joins []jsonJoin `json:"joins,omitempty"`
+  Where map[string]string `json:"where,omitempty"`
+  IsNull []string `json:"isNull,omitempty"`
+  IsNotNull []string `json:"isNotNull,omitempty"`
+  Filter []jsonCond `json:"filter,omitempty"`
+  GroupBy []string `json:"groupBy,omitempty"`
+  OrderBy []jsonOrder `json:"orderBy,omitempty"`
+  Limit int `json:"limit,omitempty"`
+  Offset int `json:"offset,omitempty"`
+}
+
+// MarshalJSON stores the whole query, e.g. for saved reports, to be loaded
+// with UnmarshalJSON and rendered elsewhere. Expressions added with SelectExpr
+// or GroupByExpr are trusted SQL that UnmarshalJSON cannot load, so a Q with
+// any fails.
+func (q *Q) MarshalJSON() ([]byte, error) {
+  if len(q.exprs) > 0 {
+    return nil, fmt.Errorf("goqdsl: a query with expressions cannot be stored as JSON")
+  }
+  j := jsonQ{Select: q.fields, From: q.from, Alias: q.alias, Into: q.into, Where: q.criteria, GroupBy: q.groupBy, Limit: q.limit, Offset: q.offset}
+  for _, join := range q.joins {
+    j.Joins = append(j.Joins, jsonJoin{Table: join.table, Left: join.left, Right: join.right})
+  }
+  for _, n := range q.nulls {
+    if n.not {
+      j.IsNotNull = append(j.IsNotNull, n.column)
+    } else {
+      j.IsNull = append(j.IsNull, n.column)
+    }
+  }
+  for _, c := range q.conds {
+    if err := c.check(); err != nil {
+      return nil, err
+    }
+    j.Filter = append(j.Filter, condJSON(c))
+  }
+  for _, o := range q.order {
+    j.OrderBy = append(j.OrderBy, jsonOrder{Column: o.column, Desc: o.desc})
+  }
+  return json.Marshal(j)
+}
+
+// UnmarshalJSON loads a query stored with MarshalJSON. The JSON may come from
+// anywhere, so its identifiers must pass CheckIdentifiers and it holds no
+// expressions. On an error q is left unchanged.
+func (q *Q) UnmarshalJSON(b []byte) error {
+  q.mutate()
+  var j jsonQ
+  if err := json.Unmarshal(b, &j); err != nil {
+    return err
+  }
+  loaded := Q{fields: j.Select, from: j.From, alias: j.Alias, into: j.Into, criteria: j.Where, groupBy: j.GroupBy, limit: j.Limit, offset: j.Offset, built: new(built)}
+  for _, join := range j.Joins {
+    loaded.joins = append(loaded.joins, Join{table: join.Table, left: join.Left, right: join.Right})
+  }
+  loaded.IsNull(j.IsNull...)
+  loaded.IsNotNull(j.IsNotNull...)
+  for _, jc := range j.Filter {
+    c, err := jc.cond()
+    if err != nil {
+      return err
+    }
+    loaded.conds = append(loaded.conds, c)
+  }
+  for _, o := range j.OrderBy {
+    loaded.order = append(loaded.order, Order{column: o.Column, desc: o.Desc})
+  }
+  if err := loaded.checkIdentifiers(); err != nil {
+    return err
+  }
+  *q = loaded
+  return nil
+}
+
+func condJSON(c Cond) jsonCond {
+  j := jsonCond{Column: c.column, Op: c.op, Values: c.values, Zone: c.zone, Cast: c.cast}
+  for _, sub := range c.conds {
+    j.Conds = append(j.Conds, condJSON(sub))
+  }
+  return j
+}
+
+// cond checks the operator, its column and its number of values and
+// conditions, which write relies on.
+func (j jsonCond) cond() (Cond, error) {
+
+  if !ops[j.Op] {
+    return Cond{}, fmt.Errorf("goqdsl: unknown filter operator %q", j.Op)
+  }
+
+  values, conds := 1, 0
+  switch j.Op {
+  case "RANGE":
+    values = 2
+  case "IN":
+    values = len(j.Values)
+  case "IS NULL", "IS NOT NULL":
+    values = 0
+  case "AND", "OR":
+    values, conds = 0, len(j.Conds)
+  case "NOT":
+    values, conds = 0, 1
+  }
+  if len(j.Values) != values || len(j.Conds) != conds || (j.Column == "") != (j.Op == "AND" || j.Op == "OR" || j.Op == "NOT") {
+    return Cond{}, fmt.Errorf("goqdsl: %d values and %d conditions for filter operator %s", len(j.Values), len(j.Conds), j.Op)
+  }
+
+  if j.Cast != "" && !castType.MatchString(j.Cast) {
+    return Cond{}, fmt.Errorf("goqdsl: invalid cast type %q", j.Cast)
+  }
+  c := Cond{column: j.Column, op: j.Op, values: j.Values, zone: j.Zone, cast: j.Cast}
+  for _, sub := range j.Conds {
+    sc, err := sub.cond()
+    if err != nil {
+      return Cond{}, err
+    }
+    c.conds = append(c.conds, sc)
+  }
+  return c, nil
+}
+
+// end
@@ -0,0 +1,90 @@
+package goqdsl
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+  q := NewQ().Select("uuid", "name").From("foo").
+    InnerJoin([]Join{{table: "bar", left: "foo.uuid", right: "bar.foo_uuid"}}).
+    Where(map[string]string{"name": "bar"}).IsNull("deleted_at")
+
+  b, err := json.Marshal(q)
+  if err != nil {
+    t.Fatal(err)
+  }
+  expected := `{"select":["uuid","name"],"from":"foo","joins":[{"table":"bar","left":"foo.uuid","right":"bar.foo_uuid"}],"where":{"name":"bar"},"isNull":["deleted_at"]}`
+  if string(b) != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, b)
+  }
+
+  loaded := NewQ()
+  if err := json.Unmarshal(b, loaded); err != nil {
+    t.Fatal(err)
+  }
+  if !reflect.DeepEqual(loaded, q) {
+    t.Errorf("expected %#v, got %#v", q, loaded)
+  }
+}
+
+func TestJSONFilter(t *testing.T) {
+  q := NewQ().Select("uuid").From("foo").Filter(Column[string]("name").In("bar", "baz")).OrderBy(Desc("created"))
+
+  b, err := json.Marshal(q)
+  if err != nil {
+    t.Fatal(err)
+  }
+  loaded := NewQ()
+  if err := json.Unmarshal(b, loaded); err != nil {
+    t.Fatal(err)
+  }
+  if !reflect.DeepEqual(loaded, q) {
+    t.Errorf("expected %#v, got %#v", q, loaded)
+  }
+
+  bad := `{"select":["uuid"],"from":"foo","filter":[{"column":"name","op":"= 1 OR 1 =","values":[1]}]}`
+  if err := json.Unmarshal([]byte(bad), NewQ()); err == nil {
+    t.Error("expected an error for an unknown operator")
+  }
+}
+
+func TestJSONExpr(t *testing.T) {
+  for _, q := range []*Q{
+    NewQ().Select("uuid").SelectExpr(Unsafe("now() - created AS age")).From("foo"),
+    NewQ().Select("kind").From("foo").GroupByExpr(Unsafe("date_trunc('day', created)")),
+  } {
+    if _, err := json.Marshal(q); err == nil {
+      t.Errorf("%s: expected an error for an expression", q.Query())
+    }
+  }
+}
+
+func TestJSONIdentifiers(t *testing.T) {
+  q := NewQ().Select("uuid").From("foo")
+  for _, bad := range []string{
+    `{"select":["uuid; DROP TABLE foo"],"from":"foo"}`,
+    `{"select":["uuid"],"from":"foo; DROP TABLE foo"}`,
+    `{"select":["uuid"],"from":"foo","orderBy":[{"column":"1; DROP TABLE foo"}]}`,
+  } {
+    var identErr *IdentifierError
+    if err := json.Unmarshal([]byte(bad), q); !errors.As(err, &identErr) {
+      t.Errorf("%s: expected an IdentifierError, got %v", bad, err)
+    }
+  }
+  if sql := q.Query(); sql != "SELECT uuid FROM foo " {
+    t.Errorf("expected a failed load to leave q alone, got %s", sql)
+  }
+
+  loaded := NewQ()
+  if err := json.Unmarshal([]byte(`{"select":["uuid"],"from":"foo"}`), loaded); err != nil {
+    t.Fatal(err)
+  }
+  if sql := loaded.Query(); sql != "SELECT uuid FROM foo " {
+    t.Errorf("unexpected sql: %s", sql)
+  }
+}
+
+// end
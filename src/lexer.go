@@ -0,0 +1,156 @@
+package goqdsl
+
+import (
+	"strings"
+)
+
+type tokenKind int
+
+const (
+  tokenCode tokenKind = iota
+  tokenString // '...', E'...' or $tag$...$tag$
+  tokenIdent // "..."
+  tokenComment // -- or /* */
+  tokenParam // @name, text holds the name
+)
+
+// lex splits sql into tokens. Only @name outside strings, quoted identifiers
+// and comments is a parameter; the @>, <@ and @@ operators are code.
+func lex(sql string, emit func(kind tokenKind, text string)) {
+
+  start := 0
+  flush := func(end int) {
+    if end > start {
+      emit(tokenCode, sql[start:end])
+    }
+  }
+
+  for i := 0; i < len(sql); i++ {
+    c := sql[i]
+    var end int
+    var kind tokenKind
+
+    switch {
+    case c == '\'':
+      escapes := i > 0 && (sql[i-1] == 'E' || sql[i-1] == 'e') && (i < 2 || !isParamByte(sql[i-2]))
+      end, kind = quotedEnd(sql, i, '\'', escapes), tokenString
+    case c == '"':
+      end, kind = quotedEnd(sql, i, '"', false), tokenIdent
+    case c == '-' && strings.HasPrefix(sql[i:], "--"):
+      end, kind = lineEnd(sql, i), tokenComment
+    case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+      end, kind = blockCommentEnd(sql, i), tokenComment
+    case c == '$' && (i == 0 || !isParamByte(sql[i-1])):
+      end, kind = dollarEnd(sql, i), tokenString
+      if end < 0 {
+        continue
+      }
+    case c == '@':
+      if i+1 < len(sql) && (sql[i+1] == '@' || sql[i+1] == '>') {
+        i++
+        continue
+      }
+      if i > 0 && sql[i-1] == '<' || i+1 >= len(sql) || !isParamStart(sql[i+1]) {
+        continue
+      }
+      end = i + 1
+      for end < len(sql) && isParamByte(sql[end]) {
+        end++
+      }
+      flush(i)
+      emit(tokenParam, sql[i+1:end])
+      start, i = end, end-1
+      continue
+    default:
+      continue
+    }
+
+    flush(i)
+    emit(kind, sql[i:end])
+    start, i = end, end-1
+  }
+  flush(len(sql))
+}
+
+func isParamStart(c byte) bool {
+  return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isParamByte(c byte) bool {
+  return isParamStart(c) || c >= '0' && c <= '9'
+}
+
+// quotedEnd returns the index after the quote closing the one at i, where a
+// doubled quote, or with escapes a backslash, escapes it.
+func quotedEnd(sql string, i int, quote byte, escapes bool) int {
+  for j := i + 1; j < len(sql); j++ {
+    switch {
+    case escapes && sql[j] == '\\':
+      j++
+    case sql[j] == quote && j+1 < len(sql) && sql[j+1] == quote:
+      j++
+    case sql[j] == quote:
+      return j + 1
+    }
+  }
+  return len(sql)
+}
+
+func lineEnd(sql string, i int) int {
+  if n := strings.IndexByte(sql[i:], '\n'); n >= 0 {
+    return i + n + 1
+  }
+  return len(sql)
+}
+
+// blockCommentEnd handles nesting, which PostgreSQL allows.
+func blockCommentEnd(sql string, i int) int {
+  depth := 0
+  for j := i; j+1 < len(sql); j++ {
+    switch sql[j : j+2] {
+    case "/*":
+      depth++
+      j++
+    case "*/":
+      depth--
+      j++
+      if depth == 0 {
+        return j + 1
+      }
+    }
+  }
+  return len(sql)
+}
+
+// dollarEnd returns the end of the dollar-quoted string at i, or -1 when $
+// starts no tag, e.g. in a $1 placeholder.
+func dollarEnd(sql string, i int) int {
+  j := i + 1
+  for j < len(sql) && isParamStart(sql[j]) || j > i+1 && j < len(sql) && isParamByte(sql[j]) {
+    j++
+  }
+  if j >= len(sql) || sql[j] != '$' {
+    return -1
+  }
+  tag := sql[i : j+1]
+  if n := strings.Index(sql[j+1:], tag); n >= 0 {
+    return j + 1 + n + len(tag)
+  }
+  return len(sql)
+}
+
+// Params returns the names of the @name parameters in sql, once each in order
+// of appearance, skipping strings, quoted identifiers and comments.
+func Params(sql string) []string {
+  var names []string
+  seen := map[string]bool{}
+  lex(sql, func(kind tokenKind, text string) {
+    if kind == tokenParam && !seen[text] {
+      seen[text] = true
+      names = append(names, text)
+    }
+  })
+  return names
+}
+
+// end
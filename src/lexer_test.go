@@ -0,0 +1,62 @@
+package goqdsl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func params(sql string) []string {
+  var names []string
+  lex(sql, func(kind tokenKind, text string) {
+    if kind == tokenParam {
+      names = append(names, text)
+    }
+  })
+  return names
+}
+
+func TestLexParams(t *testing.T) {
+  for sql, expected := range map[string][]string{
+    "SELECT a FROM t WHERE b = @b AND c = @c_1": {"b", "c_1"},
+    "SELECT data @> @filter, tags <@ @tags FROM t": {"filter", "tags"},
+    "SELECT doc @@to_tsquery(@q) FROM t": {"q"},
+    "SELECT '@no', 'it''s @no', E'\\' @no', @yes": {"yes"},
+    `SELECT "@no" FROM t -- @no` + "\nWHERE a = @yes": {"yes"},
+    "SELECT /* @no /* nested */ @no */ @yes": {"yes"},
+    "SELECT $$ @no $$, $fn$ @no $fn$, $1, @yes": {"yes"},
+    "SELECT a@b, @1": {"b"},
+  } {
+    if got := params(sql); !reflect.DeepEqual(got, expected) {
+      t.Errorf("%s: expected %v, got %v", sql, expected, got)
+    }
+  }
+}
+
+func TestParams(t *testing.T) {
+  if got := Params("SELECT '@no', @b FROM t WHERE a = @a OR b = @b"); !reflect.DeepEqual(got, []string{"b", "a"}) {
+    t.Errorf("unexpected params: %v", got)
+  }
+}
+
+func TestLexRoundTrip(t *testing.T) {
+  sql := "SELECT 'a', \"b\", $x$c$x$ -- d\n/* e */ FROM t WHERE f = @f"
+  var out string
+  lex(sql, func(kind tokenKind, text string) {
+    if kind == tokenParam {
+      text = "@" + text
+    }
+    out += text
+  })
+  if out != sql {
+    t.Errorf("expected %q, got %q", sql, out)
+  }
+}
+
+func TestToSQLSkipsLiterals(t *testing.T) {
+  sql := ToSQL(raw{"SELECT '@name', data @> @name FROM t", args{"name": "x"}})
+  if sql != "SELECT '@name', data @> 'x' FROM t" {
+    t.Errorf("unexpected sql: %s", sql)
+  }
+}
+
+// end
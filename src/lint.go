@@ -0,0 +1,106 @@
+package goqdsl
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type LintKind string
+
+const (
+  LintParens LintKind = "parens"
+  LintMissingArg LintKind = "missing-arg"
+  LintUnusedArg LintKind = "unused-arg"
+  LintDuplicateParam LintKind = "duplicate-param"
+  LintCartesian LintKind = "cartesian"
+)
+
+type Warning struct {
+  Kind LintKind
+  Message string
+}
+
+func (w Warning) String() string {
+  return string(w.Kind) + ": " + w.Message
+}
+
+var (
+  commaJoin = regexp.MustCompile(`(?i)\bFROM\s+[\w.]+(\s+(AS\s+)?\w+)?\s*,\s*[\w.]+`)
+  crossJoin = regexp.MustCompile(`(?i)\bCROSS\s+JOIN\b`)
+  where = regexp.MustCompile(`(?i)\bWHERE\b`)
+)
+
+// Lint checks the SQL b builds for unbalanced parentheses, placeholders
+// without an argument and the other way around, placeholders used more than
+// once, and joins that produce a cartesian product.
+func Lint(b Builder) []Warning {
+
+  sql, args := b.BuildNamed()
+  var warnings []Warning
+  warn := func(kind LintKind, msg string) {
+    warnings = append(warnings, Warning{Kind: kind, Message: msg})
+  }
+
+  var code strings.Builder
+  depth := 0
+  used := map[string]int{}
+  lex(sql, func(kind tokenKind, text string) {
+    switch kind {
+    case tokenParam:
+      used[text]++
+      code.WriteString("@" + text)
+    case tokenCode:
+      code.WriteString(text)
+      for _, c := range text {
+        switch c {
+        case '(':
+          depth++
+        case ')':
+          depth--
+          if depth < 0 {
+            warn(LintParens, "closing parenthesis without opening one")
+            depth = 0
+          }
+        }
+      }
+    default:
+      code.WriteString(" ")
+    }
+  })
+  if depth > 0 {
+    warn(LintParens, "unclosed parenthesis")
+  }
+
+  names := make([]string, 0, len(used))
+  for name := range used {
+    names = append(names, name)
+  }
+  sort.Strings(names)
+  for _, name := range names {
+    if _, ok := args[name]; !ok {
+      warn(LintMissingArg, "@"+name+" has no argument")
+    }
+    if used[name] > 1 {
+      warn(LintDuplicateParam, "@"+name+" is used more than once")
+    }
+  }
+
+  unused := []string{}
+  for name := range args {
+    if used[name] == 0 {
+      unused = append(unused, name)
+    }
+  }
+  sort.Strings(unused)
+  for _, name := range unused {
+    warn(LintUnusedArg, "argument "+name+" is not used")
+  }
+
+  if crossJoin.MatchString(code.String()) || (commaJoin.MatchString(code.String()) && !where.MatchString(code.String())) {
+    warn(LintCartesian, "tables are joined without a condition")
+  }
+  return warnings
+}
+
+// end
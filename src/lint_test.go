@@ -0,0 +1,43 @@
+package goqdsl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func kinds(warnings []Warning) []LintKind {
+  var k []LintKind
+  for _, w := range warnings {
+    k = append(k, w.Kind)
+  }
+  return k
+}
+
+func TestLintClean(t *testing.T) {
+  q := NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "bar"})
+  if w := Lint(q); len(w) != 0 {
+    t.Errorf("expected no warnings, got %v", w)
+  }
+}
+
+func TestLint(t *testing.T) {
+  for _, c := range []struct {
+    b Builder
+    expected []LintKind
+  }{
+    {raw{"SELECT (a FROM foo WHERE b = ')'", nil}, []LintKind{LintParens}},
+    {raw{"SELECT a) FROM foo", nil}, []LintKind{LintParens}},
+    {raw{"SELECT a FROM foo WHERE b = @b AND c = '@c'", args{"x": 1}}, []LintKind{LintMissingArg, LintUnusedArg}},
+    {raw{"SELECT a FROM foo WHERE b = @b OR c = @b", args{"b": 1}}, []LintKind{LintDuplicateParam}},
+    {NewQ().Select("uuid").From("foo").Where(map[string]string{"foo.uuid": "1", "foo_uuid": "2"}), nil},
+    {raw{"SELECT a FROM foo f, bar b", nil}, []LintKind{LintCartesian}},
+    {raw{"SELECT a FROM foo CROSS JOIN bar WHERE a = 1", nil}, []LintKind{LintCartesian}},
+    {raw{"SELECT a FROM foo, bar WHERE foo.id = bar.id", nil}, nil},
+  } {
+    if got := kinds(Lint(c.b)); !reflect.DeepEqual(got, c.expected) {
+      t.Errorf("%s: expected %v, got %v", c.b.Query(), c.expected, got)
+    }
+  }
+}
+
+// end
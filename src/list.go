@@ -0,0 +1,168 @@
+package goqdsl
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ListConfig says what a list endpoint accepts, see ParseList.
+type ListConfig struct {
+  Filters map[string]ListFilter
+  Sorts Sortable
+  DefaultSort string
+  PageSize int
+  MaxPageSize int
+}
+
+// ListFilter is a query parameter a list endpoint filters on. Ops are the
+// filter tag operators allowed, see Filters, eq if empty. Parse converts the
+// text of a value for binding; without it the text is bound.
+type ListFilter struct {
+  Column string
+  Ops []string
+  Parse func(string) (any, error)
+}
+
+// List is a parsed list request. Q holds the filters and ordering; pass it
+// with Page and Size to goqdslpgx.FetchPage, or use Paged.
+type List struct {
+  Q *Q
+  Page int
+  Size int
+}
+
+// Paged returns Q limited to the page. ParseList keeps its offset in range.
+func (l List) Paged() *Q {
+  return l.Q.Clone().Limit(l.Size).Offset((l.Page - 1) * l.Size)
+}
+
+// ListError reports a bad query parameter, for a 400 response.
+type ListError struct {
+  Param string
+  Err error
+}
+
+func (e *ListError) Error() string {
+  return "goqdsl: query parameter " + e.Param + ": " + e.Err.Error()
+}
+
+func (e *ListError) Unwrap() error {
+  return e.Err
+}
+
+// ParseList applies the query parameters of r to a clone of base:
+//
+//  ?name=bar&age[gte]=30&kind[in]=a,b&sort=-created&page=2&page_size=50
+//
+// Filters are named params, optionally with an [op]; in takes a comma
+// separated list. sort is read by cfg.Sorts, falling back to cfg.DefaultSort.
+// page starts at 1 and page_size defaults to cfg.PageSize, or 20, and is
+// capped at cfg.MaxPageSize, or 100. A page whose offset overflows an int is
+// rejected. Other params are ignored, except an unknown filter with an [op].
+func ParseList(r *http.Request, base *Q, cfg ListConfig) (List, error) {
+
+  params := r.URL.Query()
+  l := List{Q: base.Clone(), Page: 1, Size: cfg.PageSize}
+  if l.Size <= 0 {
+    l.Size = 20
+  }
+
+  names := make([]string, 0, len(params))
+  for name := range params {
+    names = append(names, name)
+  }
+  sort.Strings(names)
+
+  for _, param := range names {
+    name, op := param, "eq"
+    if i := strings.IndexByte(param, '['); i > 0 && strings.HasSuffix(param, "]") {
+      name, op = param[:i], param[i+1:len(param)-1]
+    }
+    f, ok := cfg.Filters[name]
+    if !ok {
+      if param != name {
+        return l, &ListError{Param: param, Err: fmt.Errorf("unknown filter")}
+      }
+      continue
+    }
+    c, err := f.cond(op, params.Get(param))
+    if err != nil {
+      return l, &ListError{Param: param, Err: err}
+    }
+    l.Q.Filter(c)
+  }
+
+  sortParam := params.Get("sort")
+  if sortParam == "" {
+    sortParam = cfg.DefaultSort
+  }
+  orders, err := cfg.Sorts.Parse(sortParam)
+  if err != nil {
+    return l, &ListError{Param: "sort", Err: err}
+  }
+  l.Q.OrderBy(orders...)
+
+  if l.Page, err = positive(params, "page", l.Page); err != nil {
+    return l, err
+  }
+  if l.Size, err = positive(params, "page_size", l.Size); err != nil {
+    return l, err
+  }
+  maxSize := cfg.MaxPageSize
+  if maxSize <= 0 {
+    maxSize = 100
+  }
+  l.Size = min(l.Size, maxSize)
+  if l.Page-1 > math.MaxInt/l.Size {
+    return l, &ListError{Param: "page", Err: fmt.Errorf("page %d out of range", l.Page)}
+  }
+  return l, nil
+}
+
+func (f ListFilter) cond(op, text string) (Cond, error) {
+
+  allowed := f.Ops
+  if len(allowed) == 0 {
+    allowed = []string{"eq"}
+  }
+  if _, known := filterOps[op]; !known || !slices.Contains(allowed, op) {
+    return Cond{}, fmt.Errorf("operator %s not allowed", op)
+  }
+
+  texts := []string{text}
+  if op == "in" {
+    texts = strings.Split(text, ",")
+  }
+  values := make([]any, len(texts))
+  for i, t := range texts {
+    values[i] = t
+    if f.Parse != nil {
+      v, err := f.Parse(t)
+      if err != nil {
+        return Cond{}, err
+      }
+      values[i] = v
+    }
+  }
+  return Cond{column: f.Column, op: filterOps[op], values: values}, nil
+}
+
+func positive(params url.Values, name string, def int) (int, error) {
+  v, ok := params[name]
+  if !ok || v[0] == "" {
+    return def, nil
+  }
+  n, err := strconv.Atoi(v[0])
+  if err != nil || n < 1 {
+    return 0, &ListError{Param: name, Err: fmt.Errorf("not a positive number: %q", v[0])}
+  }
+  return n, nil
+}
+
+// end
@@ -0,0 +1,75 @@
+package goqdsl
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+var fooList = ListConfig{
+  Filters: map[string]ListFilter{
+    "name": {Column: "name", Ops: []string{"eq", "ilike"}},
+    "age": {Column: "age", Ops: []string{"gte", "lt"}, Parse: func(s string) (any, error) { return strconv.Atoi(s) }},
+    "kind": {Column: "kind", Ops: []string{"in"}},
+  },
+  Sorts: Sortable{"created": "created", "name": "name"},
+  DefaultSort: "-created",
+  MaxPageSize: 100,
+}
+
+func TestParseList(t *testing.T) {
+  r := httptest.NewRequest("GET", "/foos?name[ilike]=%25bar%25&age[gte]=30&kind[in]=a,b&sort=name&page=3&page_size=500&token=x", nil)
+  base := NewQ().Select("uuid", "name").From("foo").Freeze()
+
+  l, err := ParseList(r, base, fooList)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if l.Page != 3 || l.Size != 100 {
+    t.Errorf("expected page 3 of 100, got %d of %d", l.Page, l.Size)
+  }
+
+  sql, args := l.Paged().BuildNamed()
+  expected := "SELECT uuid, name FROM foo WHERE age >= @age AND   kind IN (@kind, @kind_2) AND   name ILIKE @name ORDER BY name ASC LIMIT 100 OFFSET 200 "
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+  if args["age"] != 30 || args["kind_2"] != "b" || args["name"] != "%bar%" {
+    t.Errorf("unexpected args: %v", args)
+  }
+}
+
+func TestParseListDefaults(t *testing.T) {
+  l, err := ParseList(httptest.NewRequest("GET", "/foos", nil), NewQ().Select("uuid").From("foo"), fooList)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if sql := l.Paged().Query(); sql != "SELECT uuid FROM foo ORDER BY created DESC LIMIT 20 " {
+    t.Errorf("unexpected sql: %s", sql)
+  }
+}
+
+func TestParseListMaxPageSize(t *testing.T) {
+  cfg := fooList
+  cfg.MaxPageSize = 0
+  l, err := ParseList(httptest.NewRequest("GET", "/foos?page_size=1000000", nil), NewQ().Select("uuid").From("foo"), cfg)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if l.Size != 100 {
+    t.Errorf("expected a page size of 100, got %d", l.Size)
+  }
+}
+
+func TestParseListErrors(t *testing.T) {
+  for _, query := range []string{"name[gte]=x", "secret[eq]=x", "age[gte]=old", "sort=secret", "page=0", "page_size=x", "page=9223372036854775807"} {
+    _, err := ParseList(httptest.NewRequest("GET", "/foos?"+query, nil), NewQ().Select("uuid").From("foo"), fooList)
+    var listErr *ListError
+    if !errors.As(err, &listErr) {
+      t.Errorf("%s: expected a ListError, got %v", query, err)
+    }
+  }
+}
+
+// end
@@ -0,0 +1,302 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	goqdsl "github.com/raugustinus/goqdsl/src"
+)
+
+// A Migration is either SQL (UpSQL/DownSQL) or Go (Up/Down). When both are
+// set the Go function wins.
+type Migration struct {
+  Version int64
+  Name string
+  UpSQL string
+  DownSQL string
+  Up func(ctx context.Context, tx pgx.Tx) error
+  Down func(ctx context.Context, tx pgx.Tx) error
+}
+
+type Runner struct {
+  acquire func(ctx context.Context) (conn, func(), error)
+  table string
+  migrations map[int64]*Migration
+}
+
+// conn is the part of *pgxpool.Conn the runner uses.
+type conn interface {
+  Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+  Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+  Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+func NewRunner(pool *pgxpool.Pool) *Runner {
+  acquire := func(ctx context.Context) (conn, func(), error) {
+    c, err := pool.Acquire(ctx)
+    if err != nil {
+      return nil, nil, err
+    }
+    return c, c.Release, nil
+  }
+  return &Runner{acquire: acquire, table: "schema_migrations", migrations: map[int64]*Migration{}}
+}
+
+func (r *Runner) Table(t string) *Runner {
+  r.table = t
+  return r
+}
+
+func (r *Runner) Add(migrations ...Migration) *Runner {
+  for _, m := range migrations {
+    m := m
+    r.migrations[m.Version] = &m
+  }
+  return r
+}
+
+// LoadDir reads files named <version>_<name>.up.sql and <version>_<name>.down.sql.
+func (r *Runner) LoadDir(fsys fs.FS, dir string) error {
+
+  entries, err := fs.ReadDir(fsys, dir)
+  if err != nil {
+    return err
+  }
+
+  for _, e := range entries {
+    if e.IsDir() || !strings.HasSuffix(e.Name(), ".sql") {
+      continue
+    }
+
+    version, name, up, err := parseFilename(e.Name())
+    if err != nil {
+      return err
+    }
+
+    b, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+    if err != nil {
+      return err
+    }
+
+    m, ok := r.migrations[version]
+    if !ok {
+      m = &Migration{Version: version, Name: name}
+      r.migrations[version] = m
+    }
+    if up {
+      m.UpSQL = string(b)
+    } else {
+      m.DownSQL = string(b)
+    }
+  }
+  return nil
+}
+
+func parseFilename(name string) (int64, string, bool, error) {
+
+  var up bool
+  switch {
+  case strings.HasSuffix(name, ".up.sql"):
+    up = true
+    name = strings.TrimSuffix(name, ".up.sql")
+  case strings.HasSuffix(name, ".down.sql"):
+    name = strings.TrimSuffix(name, ".down.sql")
+  default:
+    return 0, "", false, fmt.Errorf("migration %s: expected .up.sql or .down.sql", name)
+  }
+
+  v, rest, _ := strings.Cut(name, "_")
+  version, err := strconv.ParseInt(v, 10, 64)
+  if err != nil {
+    return 0, "", false, fmt.Errorf("migration %s: invalid version: %w", name, err)
+  }
+  return version, rest, up, nil
+}
+
+func (r *Runner) sorted() []*Migration {
+  ms := make([]*Migration, 0, len(r.migrations))
+  for _, m := range r.migrations {
+    ms = append(ms, m)
+  }
+  sort.Slice(ms, func(i, j int) bool { return ms[i].Version < ms[j].Version })
+  return ms
+}
+
+// Applied returns the applied versions in ascending order.
+func (r *Runner) Applied(ctx context.Context) ([]int64, error) {
+  var versions []int64
+  err := r.locked(ctx, func(conn conn) error {
+    applied, err := r.applied(ctx, conn)
+    for _, a := range applied {
+      versions = append(versions, a.version)
+    }
+    return err
+  })
+  return versions, err
+}
+
+// Up applies all pending migrations, each in its own transaction. It fails
+// without applying anything when an applied SQL migration was changed since.
+func (r *Runner) Up(ctx context.Context) error {
+  return r.locked(ctx, func(conn conn) error {
+
+    applied, err := r.applied(ctx, conn)
+    if err != nil {
+      return err
+    }
+    done := map[int64]bool{}
+    for _, a := range applied {
+      done[a.version] = true
+      m, ok := r.migrations[a.version]
+      if ok && a.checksum != "" && a.checksum != m.checksum() {
+        return fmt.Errorf("migration %d (%s): checksum mismatch, it changed after it was applied", m.Version, m.Name)
+      }
+    }
+
+    for _, m := range r.sorted() {
+      if done[m.Version] {
+        continue
+      }
+      err := r.apply(ctx, conn, m, m.Up, m.UpSQL,
+        "INSERT INTO "+r.table+" (version, name, checksum) VALUES ($1, $2, $3)", m.Version, m.Name, m.checksum())
+      if err != nil {
+        return err
+      }
+    }
+    return nil
+  })
+}
+
+// Down reverts the last steps applied migrations.
+func (r *Runner) Down(ctx context.Context, steps int) error {
+  return r.locked(ctx, func(conn conn) error {
+
+    applied, err := r.applied(ctx, conn)
+    if err != nil {
+      return err
+    }
+
+    for i := len(applied) - 1; i >= 0 && steps > 0; i, steps = i-1, steps-1 {
+      m, ok := r.migrations[applied[i].version]
+      if !ok {
+        return fmt.Errorf("migration %d: applied but not registered", applied[i].version)
+      }
+      err := r.apply(ctx, conn, m, m.Down, m.DownSQL,
+        "DELETE FROM "+r.table+" WHERE version = $1", m.Version)
+      if err != nil {
+        return err
+      }
+    }
+    return nil
+  })
+}
+
+func (r *Runner) apply(ctx context.Context, conn conn, m *Migration, fn func(context.Context, pgx.Tx) error, sql string, record string, args ...any) error {
+
+  tx, err := conn.Begin(ctx)
+  if err != nil {
+    return err
+  }
+  defer tx.Rollback(ctx)
+
+  switch {
+  case fn != nil:
+    err = fn(ctx, tx)
+  case sql != "":
+    _, err = tx.Exec(ctx, sql)
+  default:
+    err = fmt.Errorf("no statements")
+  }
+  if err != nil {
+    return fmt.Errorf("migration %d (%s): %w", m.Version, m.Name, err)
+  }
+
+  if _, err := tx.Exec(ctx, record, args...); err != nil {
+    return err
+  }
+  return tx.Commit(ctx)
+}
+
+// locked runs fn on a single connection holding an advisory lock derived from
+// the table name, so concurrent runners wait for each other. The migrations
+// table is created under the lock too.
+func (r *Runner) locked(ctx context.Context, fn func(conn) error) error {
+
+  conn, release, err := r.acquire(ctx)
+  if err != nil {
+    return err
+  }
+  defer release()
+
+  key := r.lockKey()
+  if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", key); err != nil {
+    return err
+  }
+  defer conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", key)
+
+  if err := r.ensureTable(ctx, conn); err != nil {
+    return err
+  }
+  return fn(conn)
+}
+
+func (r *Runner) lockKey() int64 {
+  h := fnv.New64a()
+  h.Write([]byte(r.table))
+  return int64(h.Sum64())
+}
+
+func (r *Runner) ensureTable(ctx context.Context, conn conn) error {
+  _, err := conn.Exec(ctx, goqdsl.CreateTable(r.table).
+    Column("version", "bigint", goqdsl.PrimaryKey).
+    Column("name", "text", goqdsl.NotNull).
+    Column("checksum", "text", goqdsl.NotNull, goqdsl.Default("''")).
+    Column("applied", "timestamp", goqdsl.NotNull, goqdsl.Default("now()")).
+    IfNotExists().
+    Query())
+  return err
+}
+
+// checksum identifies the SQL of an up migration. Go migrations have none.
+func (m *Migration) checksum() string {
+  if m.Up != nil {
+    return ""
+  }
+  h := fnv.New64a()
+  h.Write([]byte(m.UpSQL))
+  return strconv.FormatUint(h.Sum64(), 16)
+}
+
+type record struct {
+  version int64
+  checksum string
+}
+
+func (r *Runner) applied(ctx context.Context, conn conn) ([]record, error) {
+  rows, err := conn.Query(ctx, goqdsl.NewQ().Select("version", "checksum").From(r.table).OrderBy(goqdsl.Asc("version")).Query())
+  if err != nil {
+    return nil, err
+  }
+  defer rows.Close()
+
+  var rs []record
+  for rows.Next() {
+    var a record
+    if err := rows.Scan(&a.version, &a.checksum); err != nil {
+      return nil, err
+    }
+    rs = append(rs, a)
+  }
+  return rs, rows.Err()
+}
+
+// end
@@ -0,0 +1,233 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestLoadDir(t *testing.T) {
+  fsys := fstest.MapFS{
+    "sql/0002_add_index.up.sql": {Data: []byte("CREATE INDEX foo_name ON foo (name)")},
+    "sql/0002_add_index.down.sql": {Data: []byte("DROP INDEX foo_name")},
+    "sql/0001_create_foo.up.sql": {Data: []byte("CREATE TABLE foo (name text)")},
+    "sql/README": {Data: []byte("ignored")},
+  }
+
+  r := NewRunner(nil)
+  if err := r.LoadDir(fsys, "sql"); err != nil {
+    t.Fatal(err)
+  }
+
+  ms := r.sorted()
+  if len(ms) != 2 {
+    t.Fatalf("expected 2 migrations, got %d", len(ms))
+  }
+  if ms[0].Version != 1 || ms[0].Name != "create_foo" || ms[0].DownSQL != "" {
+    t.Errorf("unexpected first migration: %+v", ms[0])
+  }
+  if ms[1].Version != 2 || ms[1].UpSQL == "" || ms[1].DownSQL != "DROP INDEX foo_name" {
+    t.Errorf("unexpected second migration: %+v", ms[1])
+  }
+}
+
+func TestLoadDirInvalidName(t *testing.T) {
+  fsys := fstest.MapFS{
+    "sql/first.up.sql": {Data: []byte("SELECT 1")},
+  }
+  if err := NewRunner(nil).LoadDir(fsys, "sql"); err == nil {
+    t.Error("expected error for migration without numeric version")
+  }
+}
+
+// fakeConn logs statements and answers the applied query with records.
+type fakeConn struct {
+  records []record
+  log []string
+}
+
+func (c *fakeConn) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+  c.log = append(c.log, strings.TrimSpace(fmt.Sprint(sql, " ", args)))
+  return pgconn.CommandTag{}, nil
+}
+
+func (c *fakeConn) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+  c.log = append(c.log, "SELECT applied")
+  return &fakeRows{records: c.records, i: -1}, nil
+}
+
+func (c *fakeConn) Begin(ctx context.Context) (pgx.Tx, error) {
+  return &fakeTx{conn: c}, nil
+}
+
+type fakeTx struct {
+  pgx.Tx
+  conn *fakeConn
+  done bool
+}
+
+func (tx *fakeTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+  return tx.conn.Exec(ctx, sql, args...)
+}
+
+func (tx *fakeTx) Commit(ctx context.Context) error {
+  tx.done = true
+  tx.conn.log = append(tx.conn.log, "COMMIT")
+  return nil
+}
+
+func (tx *fakeTx) Rollback(ctx context.Context) error {
+  if !tx.done {
+    tx.conn.log = append(tx.conn.log, "ROLLBACK")
+  }
+  return nil
+}
+
+type fakeRows struct {
+  pgx.Rows
+  records []record
+  i int
+}
+
+func (r *fakeRows) Next() bool {
+  r.i++
+  return r.i < len(r.records)
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+  *dest[0].(*int64) = r.records[r.i].version
+  *dest[1].(*string) = r.records[r.i].checksum
+  return nil
+}
+
+func (r *fakeRows) Err() error { return nil }
+func (r *fakeRows) Close() {}
+
+func fakeRunner(c *fakeConn) *Runner {
+  r := NewRunner(nil)
+  r.acquire = func(ctx context.Context) (conn, func(), error) {
+    return c, func() { c.log = append(c.log, "RELEASE") }, nil
+  }
+  return r
+}
+
+// statements drops the lock, table and applied query statements from log.
+func statements(log []string) []string {
+  var out []string
+  for _, s := range log {
+    if strings.Contains(s, "pg_advisory") || strings.HasPrefix(s, "CREATE TABLE") || s == "SELECT applied" || s == "RELEASE" {
+      continue
+    }
+    out = append(out, s)
+  }
+  return out
+}
+
+func TestUpOrder(t *testing.T) {
+  c := &fakeConn{records: []record{{version: 1}}}
+  r := fakeRunner(c).Add(
+    Migration{Version: 3, Name: "c", UpSQL: "UP 3"},
+    Migration{Version: 1, Name: "a", UpSQL: "UP 1"},
+    Migration{Version: 2, Name: "b", Up: func(ctx context.Context, tx pgx.Tx) error {
+      _, err := tx.Exec(ctx, "UP 2")
+      return err
+    }},
+  )
+  if err := r.Up(context.Background()); err != nil {
+    t.Fatal(err)
+  }
+
+  expected := []string{
+    "UP 2 []", "INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3) [2 b ]", "COMMIT",
+    "UP 3 []", fmt.Sprintf("INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3) [3 c %s]", r.migrations[3].checksum()), "COMMIT",
+  }
+  if got := statements(c.log); fmt.Sprint(got) != fmt.Sprint(expected) {
+    t.Errorf("expected:\n%q\ngot:\n%q", expected, got)
+  }
+}
+
+func TestDownOrder(t *testing.T) {
+  c := &fakeConn{records: []record{{version: 1}, {version: 2}, {version: 3}}}
+  r := fakeRunner(c).Add(
+    Migration{Version: 1, DownSQL: "DOWN 1"},
+    Migration{Version: 2, DownSQL: "DOWN 2"},
+    Migration{Version: 3, DownSQL: "DOWN 3"},
+  )
+  if err := r.Down(context.Background(), 2); err != nil {
+    t.Fatal(err)
+  }
+
+  expected := []string{
+    "DOWN 3 []", "DELETE FROM schema_migrations WHERE version = $1 [3]", "COMMIT",
+    "DOWN 2 []", "DELETE FROM schema_migrations WHERE version = $1 [2]", "COMMIT",
+  }
+  if got := statements(c.log); fmt.Sprint(got) != fmt.Sprint(expected) {
+    t.Errorf("expected:\n%q\ngot:\n%q", expected, got)
+  }
+}
+
+func TestUpFailureRollsBack(t *testing.T) {
+  c := &fakeConn{}
+  r := fakeRunner(c).Add(
+    Migration{Version: 1, UpSQL: "UP 1"},
+    Migration{Version: 2, Up: func(ctx context.Context, tx pgx.Tx) error { return fmt.Errorf("boom") }},
+    Migration{Version: 3, UpSQL: "UP 3"},
+  )
+  if err := r.Up(context.Background()); err == nil || !strings.Contains(err.Error(), "migration 2") {
+    t.Fatalf("expected migration 2 to fail, got %v", err)
+  }
+  got := statements(c.log)
+  if len(got) != 4 || got[2] != "COMMIT" || got[3] != "ROLLBACK" {
+    t.Errorf("expected migration 1 committed and 2 rolled back, got %q", got)
+  }
+}
+
+func TestChecksumMismatch(t *testing.T) {
+  applied := Migration{Version: 1, Name: "a", UpSQL: "CREATE TABLE foo (name text)"}
+  c := &fakeConn{records: []record{{version: 1, checksum: applied.checksum()}}}
+  r := fakeRunner(c).Add(
+    Migration{Version: 1, Name: "a", UpSQL: "CREATE TABLE foo (name text, age int)"},
+    Migration{Version: 2, Name: "b", UpSQL: "UP 2"},
+  )
+  if err := r.Up(context.Background()); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+    t.Fatalf("expected a checksum mismatch, got %v", err)
+  }
+  if got := statements(c.log); len(got) != 0 {
+    t.Errorf("expected nothing applied, got %q", got)
+  }
+
+  r.Add(applied)
+  if err := r.Up(context.Background()); err != nil {
+    t.Errorf("expected a matching checksum to pass, got %v", err)
+  }
+}
+
+func TestLocked(t *testing.T) {
+  c := &fakeConn{}
+  r := fakeRunner(c).Table("other_migrations")
+  if _, err := r.Applied(context.Background()); err != nil {
+    t.Fatal(err)
+  }
+
+  key := r.lockKey()
+  if len(c.log) != 5 {
+    t.Fatalf("unexpected statements: %q", c.log)
+  }
+  if c.log[0] != fmt.Sprint("SELECT pg_advisory_lock($1) [", key, "]") {
+    t.Errorf("expected the lock first, got %q", c.log[0])
+  }
+  if !strings.HasPrefix(c.log[1], "CREATE TABLE IF NOT EXISTS other_migrations") {
+    t.Errorf("expected the table created under the lock, got %q", c.log[1])
+  }
+  if c.log[3] != fmt.Sprint("SELECT pg_advisory_unlock($1) [", key, "]") || c.log[4] != "RELEASE" {
+    t.Errorf("expected unlock before release, got %q", c.log[3:])
+  }
+  if key == NewRunner(nil).lockKey() {
+    t.Error("expected tables to lock independently")
+  }
+}
@@ -0,0 +1,133 @@
+package goqdsl
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Model describes the table behind a struct type.
+type Model struct {
+  Table string
+  PrimaryKey []string
+  Columns []string
+}
+
+var models sync.Map // reflect.Type -> Model
+
+// RegisterModel sets the model for T. Empty fields are filled in as
+// ModelOf would.
+func RegisterModel[T any](m Model) {
+  t := reflect.TypeOf((*T)(nil)).Elem()
+  derived := deriveModel(t)
+  if m.Table == "" {
+    m.Table = derived.Table
+  }
+  if m.Columns == nil {
+    m.Columns = derived.Columns
+  }
+  if m.PrimaryKey == nil {
+    m.PrimaryKey = derived.PrimaryKey
+  }
+  models.Store(t, m)
+}
+
+// ModelOf returns the registered model for T, or derives one: the table from a
+// TableName() string method or else the lowercased type name, the columns from
+// the exported fields and their db tags as pgx.RowToStructByName reads them,
+// and the primary key from fields tagged db:"name,pk".
+func ModelOf[T any]() Model {
+  t := reflect.TypeOf((*T)(nil)).Elem()
+  if m, ok := models.Load(t); ok {
+    return m.(Model)
+  }
+  m := deriveModel(t)
+  models.Store(t, m)
+  return m
+}
+
+// SelectModel selects all columns of T's model from its table.
+func SelectModel[T any]() *Q {
+  m := ModelOf[T]()
+  return NewQ().Select(m.Columns...).From(m.Table)
+}
+
+// ModelValues returns the values of v for the columns of T's model, in
+// order. Columns without a matching field are nil.
+func ModelValues[T any](v T) []any {
+
+  m := ModelOf[T]()
+  fields := map[string]reflect.Value{}
+  collectValues(reflect.ValueOf(v), fields)
+
+  values := make([]any, len(m.Columns))
+  for i, col := range m.Columns {
+    if f, ok := fields[col]; ok {
+      values[i] = f.Interface()
+    }
+  }
+  return values
+}
+
+func collectValues(v reflect.Value, fields map[string]reflect.Value) {
+  t := v.Type()
+  for i := 0; i < t.NumField(); i++ {
+    f := t.Field(i)
+
+    tag, tagged := f.Tag.Lookup("db")
+    name, _, _ := strings.Cut(tag, ",")
+    if name == "-" || (f.PkgPath != "" && !f.Anonymous) {
+      continue
+    }
+    if f.Anonymous && f.Type.Kind() == reflect.Struct && !tagged {
+      collectValues(v.Field(i), fields)
+      continue
+    }
+
+    if name == "" {
+      name = strings.ToLower(f.Name)
+    }
+    fields[name] = v.Field(i)
+  }
+}
+
+func deriveModel(t reflect.Type) Model {
+
+  if t.Kind() != reflect.Struct {
+    panic(fmt.Sprintf("goqdsl: model %s is not a struct", t))
+  }
+
+  m := Model{Table: strings.ToLower(t.Name())}
+  if n, ok := reflect.Zero(t).Interface().(interface{ TableName() string }); ok {
+    m.Table = n.TableName()
+  }
+  collectColumns(t, &m)
+  return m
+}
+
+func collectColumns(t reflect.Type, m *Model) {
+  for i := 0; i < t.NumField(); i++ {
+    f := t.Field(i)
+
+    tag, tagged := f.Tag.Lookup("db")
+    name, opts, _ := strings.Cut(tag, ",")
+    if name == "-" || (f.PkgPath != "" && !f.Anonymous) {
+      continue
+    }
+    if f.Anonymous && f.Type.Kind() == reflect.Struct && !tagged {
+      collectColumns(f.Type, m)
+      continue
+    }
+
+    if name == "" {
+      name = strings.ToLower(f.Name)
+    }
+    m.Columns = append(m.Columns, name)
+    if opts == "pk" {
+      m.PrimaryKey = append(m.PrimaryKey, name)
+    }
+  }
+}
+
+// end
@@ -0,0 +1,49 @@
+package goqdsl
+
+import (
+	"reflect"
+	"testing"
+)
+
+type audit struct {
+  Created string
+}
+
+type user struct {
+  audit
+  Uuid string `db:"uuid,pk"`
+  FullName string `db:"full_name"`
+  Password string `db:"-"`
+  secret string
+}
+
+func (user) TableName() string {
+  return "users"
+}
+
+type account struct {
+  Id int64
+  Owner string
+}
+
+func TestSelectModel(t *testing.T) {
+  sql := SelectModel[user]().Query()
+  expected := "SELECT created, uuid, full_name FROM users "
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+  if pk := ModelOf[user]().PrimaryKey; !reflect.DeepEqual(pk, []string{"uuid"}) {
+    t.Errorf("unexpected primary key: %v", pk)
+  }
+}
+
+func TestRegisterModel(t *testing.T) {
+  RegisterModel[account](Model{Table: "accounts", PrimaryKey: []string{"id"}})
+
+  m := ModelOf[account]()
+  if m.Table != "accounts" || !reflect.DeepEqual(m.Columns, []string{"id", "owner"}) || !reflect.DeepEqual(m.PrimaryKey, []string{"id"}) {
+    t.Errorf("unexpected model: %+v", m)
+  }
+}
+
+// end
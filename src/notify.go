@@ -0,0 +1,40 @@
+package goqdsl
+
+import (
+	"strings"
+)
+
+// NotifyQ renders pg_notify rather than NOTIFY, which cannot take bind
+// parameters.
+type NotifyQ struct {
+  channel string
+  payload string
+}
+
+func Notify(channel, payload string) *NotifyQ {
+  return &NotifyQ{channel: channel, payload: payload}
+}
+
+func (n *NotifyQ) BuildNamed() (string, map[string]any) {
+  return "SELECT pg_notify(@channel, @payload)", map[string]any{"channel": n.channel, "payload": n.payload}
+}
+
+func (n *NotifyQ) BuildPositional() (string, []any) {
+  return "SELECT pg_notify($1, $2)", []any{n.channel, n.payload}
+}
+
+func (n *NotifyQ) Query() string {
+  return "SELECT pg_notify(" + quote(n.channel) + ", " + quote(n.payload) + ")"
+}
+
+// quote renders s as a string literal. With backslashes it uses E'' syntax
+// and escapes them, so the literal reads the same whether or not
+// standard_conforming_strings is on.
+func quote(s string) string {
+  if strings.ContainsRune(s, '\\') {
+    return "E'" + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), "'", "''") + "'"
+  }
+  return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// end
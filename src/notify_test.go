@@ -0,0 +1,18 @@
+package goqdsl
+
+import (
+	"testing"
+)
+
+func TestNotify(t *testing.T) {
+  n := Notify("cache", `{"key": "foo's"}`)
+
+  sql, args := n.BuildNamed()
+  if sql != "SELECT pg_notify(@channel, @payload)" || args["channel"] != "cache" || args["payload"] != `{"key": "foo's"}` {
+    t.Errorf("unexpected named build: %s %v", sql, args)
+  }
+
+  if sql := n.Query(); sql != `SELECT pg_notify('cache', '{"key": "foo''s"}')` {
+    t.Errorf("unexpected sql: %s", sql)
+  }
+}
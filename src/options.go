@@ -0,0 +1,56 @@
+package goqdsl
+
+// QOption sets part of a Q, so a query can be put together from a slice of
+// options collected elsewhere, e.g. by plugins:
+//
+//  Select([]string{"uuid", "name"}, WithFrom("foo"), WithOrder(Desc("created")), WithLimit(10))
+type QOption func(*Q)
+
+// Select starts a Q selecting fields and applies opts to it in order.
+func Select(fields []string, opts ...QOption) *Q {
+  return NewQ().Select(fields...).Apply(opts...)
+}
+
+// Apply applies opts to q in order.
+func (q *Q) Apply(opts ...QOption) *Q {
+  q.mutate()
+  for _, opt := range opts {
+    opt(q)
+  }
+  return q
+}
+
+func WithFrom(table string) QOption {
+  return func(q *Q) { q.From(table) }
+}
+
+func WithTable(t Table) QOption {
+  return func(q *Q) { q.FromTable(t) }
+}
+
+func WithJoin(joins ...Join) QOption {
+  return func(q *Q) { q.Join(joins...) }
+}
+
+func WithWhere(conds ...Cond) QOption {
+  return func(q *Q) { q.Filter(conds...) }
+}
+
+// WithWhereMap adds the entries of values as WhereMap does.
+func WithWhereMap(values map[string]any) QOption {
+  return func(q *Q) { q.WhereMap(values) }
+}
+
+func WithOrder(orders ...Order) QOption {
+  return func(q *Q) { q.OrderBy(orders...) }
+}
+
+func WithLimit(n int) QOption {
+  return func(q *Q) { q.Limit(n) }
+}
+
+func WithOffset(n int) QOption {
+  return func(q *Q) { q.Offset(n) }
+}
+
+// end
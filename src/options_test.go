@@ -0,0 +1,54 @@
+package goqdsl
+
+import (
+	"testing"
+)
+
+func TestSelectOptions(t *testing.T) {
+  opts := []QOption{WithFrom("foo"), WithWhere(Column[string]("name").Eq("bar"))}
+  opts = append(opts, WithOrder(Desc("created")), WithLimit(10), WithOffset(20))
+
+  sql, args := Select([]string{"uuid", "name"}, opts...).BuildNamed()
+  expected := "SELECT uuid, name FROM foo WHERE name = @name ORDER BY created DESC LIMIT 10 OFFSET 20 "
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+  if len(args) != 1 || args["name"] != "bar" {
+    t.Errorf("unexpected args: %v", args)
+  }
+}
+
+func TestApplyTable(t *testing.T) {
+  users, orders := T("users").As("u"), T("orders").As("o")
+  q := NewQ().Select("u.name").Apply(WithTable(users), WithJoin(orders.On("u.uuid", "o.user_uuid")), WithWhereMap(map[string]any{"o.paid": nil}))
+
+  expected := "SELECT u.name FROM users u INNER JOIN orders o ON u.uuid = o.user_uuid WHERE o.paid IS NULL "
+  if sql := q.Query(); sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+}
+
+func TestApplyFrozen(t *testing.T) {
+  q := NewQ().Select("u.name").From("users u")
+  before := q.Query()
+  q.Freeze()
+
+  defer func() {
+    if recover() == nil {
+      t.Error("expected WithJoin on a frozen Q to panic")
+    }
+    if sql := q.Query(); sql != before {
+      t.Errorf("expected the frozen Q unchanged, got %s", sql)
+    }
+  }()
+  WithJoin(T("orders").As("o").On("u.uuid", "o.user_uuid"))(q)
+}
+
+func TestApplyJoinDropsCache(t *testing.T) {
+  q := NewQ().Select("u.name").From("users u")
+  q.Query()
+  WithJoin(T("orders").As("o").On("u.uuid", "o.user_uuid"))(q)
+  if sql := q.Query(); sql != "SELECT u.name FROM users u INNER JOIN orders o ON u.uuid = o.user_uuid " {
+    t.Errorf("expected the join in a fresh build, got %s", sql)
+  }
+}
@@ -0,0 +1,84 @@
+package goqdsl
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ParseOrder reads an ordering like "created DESC, name" into Orders. Columns
+// must be identifiers and, if allowed is given, one of allowed. Directions are
+// ASC, the default, or DESC in any case.
+func ParseOrder(s string, allowed ...string) ([]Order, error) {
+
+  var orders []Order
+  for _, item := range strings.Split(s, ",") {
+    fields := strings.Fields(item)
+    if len(fields) == 0 || len(fields) > 2 {
+      return nil, fmt.Errorf("goqdsl: invalid ordering %q", strings.TrimSpace(item))
+    }
+
+    o := Order{column: fields[0]}
+    if len(fields) == 2 {
+      switch strings.ToUpper(fields[1]) {
+      case "ASC":
+      case "DESC":
+        o.desc = true
+      default:
+        return nil, fmt.Errorf("goqdsl: invalid direction %q for %s", fields[1], o.column)
+      }
+    }
+
+    if !qualifiedIdent.MatchString(o.column) || len(allowed) > 0 && !slices.Contains(allowed, o.column) {
+      return nil, fmt.Errorf("goqdsl: cannot order by %q", o.column)
+    }
+    orders = append(orders, o)
+  }
+  return orders, nil
+}
+
+// OrderByString adds the ordering in s, see ParseOrder. On error q is
+// unchanged.
+func (q *Q) OrderByString(s string, allowed ...string) (*Q, error) {
+  orders, err := ParseOrder(s, allowed...)
+  if err != nil {
+    return q, err
+  }
+  return q.OrderBy(orders...), nil
+}
+
+// Sortable maps the sort keys an API accepts to the columns they order by,
+// e.g. Sortable{"created": "a.created_at", "name": "a.name"}.
+type Sortable map[string]string
+
+// SortKeyError reports a sort key that is not in the Sortable.
+type SortKeyError struct {
+  Key string
+}
+
+func (e *SortKeyError) Error() string {
+  return "goqdsl: cannot sort by " + strconv.Quote(e.Key)
+}
+
+// Parse reads a sort parameter like "-created,name", a comma separated list
+// of keys each ordering ascending or, with a leading -, descending. Empty
+// items are skipped; unknown keys fail with a *SortKeyError.
+func (s Sortable) Parse(param string) ([]Order, error) {
+  var orders []Order
+  for _, key := range strings.Split(param, ",") {
+    key = strings.TrimSpace(key)
+    if key == "" {
+      continue
+    }
+    desc := strings.HasPrefix(key, "-")
+    column, ok := s[strings.TrimPrefix(key, "-")]
+    if !ok {
+      return nil, &SortKeyError{Key: key}
+    }
+    orders = append(orders, Order{column: column, desc: desc})
+  }
+  return orders, nil
+}
+
+// end
@@ -0,0 +1,69 @@
+package goqdsl
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseOrder(t *testing.T) {
+  orders, err := ParseOrder("created DESC, name asc,  f.uuid")
+  if err != nil {
+    t.Fatal(err)
+  }
+  expected := []Order{Desc("created"), Asc("name"), Asc("f.uuid")}
+  if !reflect.DeepEqual(orders, expected) {
+    t.Errorf("expected %v, got %v", expected, orders)
+  }
+
+  for _, s := range []string{"", "name,", "name DESC NULLS", "name SIDEWAYS", "name; DROP TABLE foo", "lower(name)"} {
+    if _, err := ParseOrder(s); err == nil {
+      t.Errorf("%q: expected an error", s)
+    }
+  }
+
+  if _, err := ParseOrder("created DESC, secret", "created", "name"); err == nil {
+    t.Error("expected an error for a column that is not allowed")
+  }
+}
+
+func TestOrderByString(t *testing.T) {
+  q, err := NewQ().Select("uuid").From("foo").OrderByString("created DESC, name", "created", "name")
+  if err != nil {
+    t.Fatal(err)
+  }
+  if sql := q.Query(); sql != "SELECT uuid FROM foo ORDER BY created DESC, name ASC " {
+    t.Errorf("unexpected sql: %s", sql)
+  }
+
+  q, err = NewQ().Select("uuid").From("foo").OrderByString("secret")
+  if err != nil {
+    t.Fatal(err)
+  }
+  if _, err := q.OrderByString("created", "name"); err == nil || q.Query() != "SELECT uuid FROM foo ORDER BY secret ASC " {
+    t.Errorf("expected an error and q unchanged, got %v: %s", err, q.Query())
+  }
+}
+
+func TestSortable(t *testing.T) {
+  s := Sortable{"created": "a.created_at", "name": "a.name"}
+
+  orders, err := s.Parse("-created, name,")
+  if err != nil {
+    t.Fatal(err)
+  }
+  expected := []Order{Desc("a.created_at"), Asc("a.name")}
+  if !reflect.DeepEqual(orders, expected) {
+    t.Errorf("expected %v, got %v", expected, orders)
+  }
+
+  var keyErr *SortKeyError
+  if _, err := s.Parse("name,-a.secret"); !errors.As(err, &keyErr) || keyErr.Key != "-a.secret" {
+    t.Errorf("expected a SortKeyError for -a.secret, got %v", err)
+  }
+  if orders, err := s.Parse(""); err != nil || len(orders) != 0 {
+    t.Errorf("expected no ordering, got %v (%v)", orders, err)
+  }
+}
+
+// end
@@ -0,0 +1,209 @@
+package goqdsl
+
+import (
+	"strings"
+	"time"
+)
+
+// PartitionQ creates a partition of a declaratively partitioned table.
+// PostgreSQL takes no bind parameters in DDL, so bounds are inlined.
+type PartitionQ struct {
+  name string
+  parent string
+  ifNotExists bool
+  from, to any
+  values []any
+}
+
+func CreatePartition(name, parent string) *PartitionQ {
+  return &PartitionQ{name: name, parent: parent}
+}
+
+// ForRange bounds a range partition from from up to, but not including, to.
+func (p *PartitionQ) ForRange(from, to any) *PartitionQ {
+  p.from, p.to, p.values = from, to, nil
+  return p
+}
+
+// ForValues lists the values of a list partition.
+func (p *PartitionQ) ForValues(values ...any) *PartitionQ {
+  p.from, p.to, p.values = nil, nil, values
+  return p
+}
+
+func (p *PartitionQ) IfNotExists() *PartitionQ {
+  p.ifNotExists = true
+  return p
+}
+
+func (p *PartitionQ) BuildNamed() (string, map[string]any) {
+  return p.Query(), nil
+}
+
+func (p *PartitionQ) BuildPositional() (string, []any) {
+  return p.Query(), nil
+}
+
+func (p *PartitionQ) Query() string {
+
+  var sb strings.Builder
+  sb.WriteString("CREATE TABLE ")
+  if p.ifNotExists {
+    sb.WriteString("IF NOT EXISTS ")
+  }
+  sb.WriteString(p.name)
+  sb.WriteString(" PARTITION OF ")
+  sb.WriteString(p.parent)
+  if p.values != nil {
+    sb.WriteString(" FOR VALUES IN (")
+    for i, v := range p.values {
+      if i > 0 {
+        sb.WriteString(", ")
+      }
+      sb.WriteString(formatValue(v))
+    }
+    sb.WriteByte(')')
+  } else {
+    sb.WriteString(" FOR VALUES FROM (")
+    sb.WriteString(formatValue(p.from))
+    sb.WriteString(") TO (")
+    sb.WriteString(formatValue(p.to))
+    sb.WriteByte(')')
+  }
+  return sb.String()
+}
+
+type DetachPartitionQ struct {
+  parent string
+  name string
+  concurrently bool
+}
+
+func DetachPartition(parent, name string) *DetachPartitionQ {
+  return &DetachPartitionQ{parent: parent, name: name}
+}
+
+// Concurrently detaches without blocking queries on the parent, but cannot
+// run in a transaction.
+func (d *DetachPartitionQ) Concurrently() *DetachPartitionQ {
+  d.concurrently = true
+  return d
+}
+
+func (d *DetachPartitionQ) BuildNamed() (string, map[string]any) {
+  return d.Query(), nil
+}
+
+func (d *DetachPartitionQ) BuildPositional() (string, []any) {
+  return d.Query(), nil
+}
+
+func (d *DetachPartitionQ) Query() string {
+  sql := "ALTER TABLE " + d.parent + " DETACH PARTITION " + d.name
+  if d.concurrently {
+    sql += " CONCURRENTLY"
+  }
+  return sql
+}
+
+type DropTableQ struct {
+  tables []string
+  ifExists bool
+}
+
+func DropTable(tables ...string) *DropTableQ {
+  return &DropTableQ{tables: tables}
+}
+
+func (d *DropTableQ) IfExists() *DropTableQ {
+  d.ifExists = true
+  return d
+}
+
+func (d *DropTableQ) BuildNamed() (string, map[string]any) {
+  return d.Query(), nil
+}
+
+func (d *DropTableQ) BuildPositional() (string, []any) {
+  return d.Query(), nil
+}
+
+func (d *DropTableQ) Query() string {
+  sql := "DROP TABLE "
+  if d.ifExists {
+    sql += "IF EXISTS "
+  }
+  return sql + strings.Join(d.tables, ", ")
+}
+
+// PartitionPeriod lays out range partitions by time, one per day, month or
+// year in UTC, named after the parent and the start of their period, e.g.
+// events_202403 for March 2024 with PartitionMonthly.
+type PartitionPeriod struct {
+  layout string
+  start func(t time.Time) time.Time
+  next func(t time.Time) time.Time
+}
+
+var (
+  PartitionDaily = PartitionPeriod{
+    layout: "20060102",
+    start: func(t time.Time) time.Time { return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC) },
+    next: func(t time.Time) time.Time { return t.AddDate(0, 0, 1) },
+  }
+  PartitionMonthly = PartitionPeriod{
+    layout: "200601",
+    start: func(t time.Time) time.Time { return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC) },
+    next: func(t time.Time) time.Time { return t.AddDate(0, 1, 0) },
+  }
+  PartitionYearly = PartitionPeriod{
+    layout: "2006",
+    start: func(t time.Time) time.Time { return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC) },
+    next: func(t time.Time) time.Time { return t.AddDate(1, 0, 0) },
+  }
+)
+
+// Name returns the partition of parent holding t. Inserting into it directly
+// skips routing through the parent.
+func (p PartitionPeriod) Name(parent string, t time.Time) string {
+  return parent + "_" + p.start(t.UTC()).Format(p.layout)
+}
+
+// Create returns the statement creating the partition of parent holding t,
+// if it does not exist yet.
+func (p PartitionPeriod) Create(parent string, t time.Time) *PartitionQ {
+  start := p.start(t.UTC())
+  return CreatePartition(p.Name(parent, start), parent).ForRange(start, p.next(start)).IfNotExists()
+}
+
+// Range returns Create for every period from the one holding from up to the
+// one holding to, not including it, e.g. the coming three months.
+func (p PartitionPeriod) Range(parent string, from, to time.Time) []*PartitionQ {
+  var qs []*PartitionQ
+  for t := p.start(from.UTC()); t.Before(p.start(to.UTC())); t = p.next(t) {
+    qs = append(qs, p.Create(parent, t))
+  }
+  return qs
+}
+
+// Before returns those of names, partitions of parent named by p, that end
+// at or before cutoff, for retention. Other names are left out.
+func (p PartitionPeriod) Before(parent string, names []string, cutoff time.Time) []string {
+  var old []string
+  for _, name := range names {
+    suffix, ok := strings.CutPrefix(name, parent+"_")
+    if !ok {
+      continue
+    }
+    start, err := time.Parse(p.layout, suffix)
+    if err != nil || start.Format(p.layout) != suffix {
+      continue
+    }
+    if !p.next(start).After(cutoff) {
+      old = append(old, name)
+    }
+  }
+  return old
+}
+
+// end
@@ -0,0 +1,62 @@
+package goqdsl
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCreatePartition(t *testing.T) {
+  sql := CreatePartition("events_eu", "events").ForValues("nl", "de").Query()
+  expected := "CREATE TABLE events_eu PARTITION OF events FOR VALUES IN ('nl', 'de')"
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+
+  sql = CreatePartition("orders_1", "orders").ForRange(0, 1000000).IfNotExists().Query()
+  expected = "CREATE TABLE IF NOT EXISTS orders_1 PARTITION OF orders FOR VALUES FROM (0) TO (1000000)"
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+}
+
+func TestPartitionPeriod(t *testing.T) {
+  created := time.Date(2024, 3, 31, 23, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+  if name := PartitionMonthly.Name("events", created); name != "events_202403" {
+    t.Errorf("expected events_202403, got %s", name)
+  }
+
+  qs := PartitionMonthly.Range("events", time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC), time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC))
+  var sqls []string
+  for _, q := range qs {
+    sqls = append(sqls, q.Query())
+  }
+  expected := []string{
+    "CREATE TABLE IF NOT EXISTS events_202411 PARTITION OF events FOR VALUES FROM ('2024-11-01T00:00:00Z') TO ('2024-12-01T00:00:00Z')",
+    "CREATE TABLE IF NOT EXISTS events_202412 PARTITION OF events FOR VALUES FROM ('2024-12-01T00:00:00Z') TO ('2025-01-01T00:00:00Z')",
+    "CREATE TABLE IF NOT EXISTS events_202501 PARTITION OF events FOR VALUES FROM ('2025-01-01T00:00:00Z') TO ('2025-02-01T00:00:00Z')",
+  }
+  if !reflect.DeepEqual(sqls, expected) {
+    t.Errorf("expected:\n%v\ngot:\n%v", expected, sqls)
+  }
+
+  names := []string{"events_20240301", "events_202402", "events_202403", "events_default", "events_eu", "events_202404"}
+  old := PartitionMonthly.Before("events", names, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))
+  if !reflect.DeepEqual(old, []string{"events_202402", "events_202403"}) {
+    t.Errorf("unexpected expired partitions: %v", old)
+  }
+}
+
+func TestDetachAndDrop(t *testing.T) {
+  if sql := DetachPartition("events", "events_202402").Concurrently().Query(); sql != "ALTER TABLE events DETACH PARTITION events_202402 CONCURRENTLY" {
+    t.Errorf("unexpected sql: %s", sql)
+  }
+  if sql := DropTable("events_202402", "events_202403").IfExists().Query(); sql != "DROP TABLE IF EXISTS events_202402, events_202403" {
+    t.Errorf("unexpected sql: %s", sql)
+  }
+  if err := CheckIdentifiers(DropTable("events; --")); err == nil {
+    t.Error("expected an identifier error")
+  }
+}
+
+// end
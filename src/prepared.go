@@ -0,0 +1,132 @@
+package goqdsl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Param is a hole in a query for Prepare, filled for each execution by
+// Prepared.Bind, e.g. WhereMap(map[string]any{"name": Param("name")}).
+type Param string
+
+// Prepared is a builder rendered once, both with named and positional
+// parameters, for queries that run often with different values.
+type Prepared struct {
+  source Builder
+  named string
+  positional string
+  names []string
+  args map[string]any
+  holes map[string][]string
+}
+
+// Bound is a Prepared with its holes filled, ready to execute.
+type Bound struct {
+  p *Prepared
+  values map[string]any
+  args map[string]any
+}
+
+// Prepare renders b. The parameters holding a Param are its holes; the other
+// values stay as they were.
+func Prepare(b Builder) *Prepared {
+
+  sql, args := b.BuildNamed()
+  p := &Prepared{source: b, named: sql, args: args, holes: map[string][]string{}}
+  for name, v := range args {
+    if h, ok := v.(Param); ok {
+      p.holes[string(h)] = append(p.holes[string(h)], name)
+    }
+  }
+
+  var sb strings.Builder
+  sb.Grow(len(sql))
+  index := map[string]int{}
+  lex(sql, func(kind tokenKind, text string) {
+    if _, ok := args[text]; kind != tokenParam || !ok {
+      if kind == tokenParam {
+        sb.WriteByte('@')
+      }
+      sb.WriteString(text)
+      return
+    }
+    i, ok := index[text]
+    if !ok {
+      p.names = append(p.names, text)
+      i = len(p.names)
+      index[text] = i
+    }
+    writePositional(&sb, i)
+  })
+  p.positional = sb.String()
+  return p
+}
+
+// Bind fills every hole by its Param name. Missing and unknown names fail.
+func (p *Prepared) Bind(values map[string]any) (*Bound, error) {
+
+  for name := range values {
+    if _, ok := p.holes[name]; !ok {
+      return nil, fmt.Errorf("goqdsl: no parameter %s to bind", name)
+    }
+  }
+
+  args := make(map[string]any, len(p.holes))
+  for hole, params := range p.holes {
+    v, ok := values[hole]
+    if !ok {
+      return nil, fmt.Errorf("goqdsl: parameter %s is not bound", hole)
+    }
+    for _, param := range params {
+      args[param] = v
+    }
+  }
+  return &Bound{p: p, values: values, args: args}, nil
+}
+
+func (b *Bound) BuildNamed() (string, map[string]any) {
+  args := make(map[string]any, len(b.p.args))
+  for k, v := range b.p.args {
+    args[k] = v
+  }
+  for k, v := range b.args {
+    args[k] = v
+  }
+  return b.p.named, args
+}
+
+func (b *Bound) BuildPositional() (string, []any) {
+  args := make([]any, len(b.p.names))
+  for i, name := range b.p.names {
+    if v, ok := b.args[name]; ok {
+      args[i] = v
+    } else {
+      args[i] = b.p.args[name]
+    }
+  }
+  return b.p.positional, args
+}
+
+func (b *Bound) Query() string {
+  return ToSQL(b)
+}
+
+// Source returns the builder that was prepared, with its holes.
+func (b *Bound) Source() Builder {
+  return b.p.source
+}
+
+// Values returns the values the holes were bound to.
+func (b *Bound) Values() map[string]any {
+  return b.values
+}
+
+// Table returns the table of the prepared builder, if it has one.
+func (b *Bound) Table() string {
+  if t, ok := b.p.source.(interface{ Table() string }); ok {
+    return t.Table()
+  }
+  return ""
+}
+
+// end
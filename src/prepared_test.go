@@ -0,0 +1,58 @@
+package goqdsl
+
+import (
+	"testing"
+)
+
+func TestPrepared(t *testing.T) {
+  p := Prepare(NewQ().Select("uuid").From("foo").
+    Where(map[string]string{"kind": "a"}).
+    Filter(fooName.EqParam("name"), Or(fooAge.EqParam("age"), fooAge.In(1, 2))).
+    WhereMap(map[string]any{"parent_uuid": Param("parent")}))
+
+  b, err := p.Bind(map[string]any{"name": "bar", "age": 42, "parent": "d3b2aa81"})
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  sql, args := b.BuildNamed()
+  expected := "SELECT uuid FROM foo WHERE kind = @kind AND   name = @name AND   (age = @age OR age IN (@age_2, @age_3)) AND   parent_uuid = @parent_uuid "
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+  if args["kind"] != "a" || args["name"] != "bar" || args["age"] != 42 || args["age_3"] != 2 || args["parent_uuid"] != "d3b2aa81" {
+    t.Errorf("unexpected args: %v", args)
+  }
+
+  sql, pargs := b.BuildPositional()
+  expected = "SELECT uuid FROM foo WHERE kind = $1 AND   name = $2 AND   (age = $3 OR age IN ($4, $5)) AND   parent_uuid = $6 "
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+  if len(pargs) != 6 || pargs[0] != "a" || pargs[1] != "bar" || pargs[2] != 42 || pargs[5] != "d3b2aa81" {
+    t.Errorf("unexpected args: %v", pargs)
+  }
+
+  other, err := p.Bind(map[string]any{"name": "baz", "age": 7, "parent": "8f1c2e04"})
+  if err != nil {
+    t.Fatal(err)
+  }
+  if _, args := other.BuildNamed(); args["name"] != "baz" || args["age"] != 7 {
+    t.Errorf("unexpected args: %v", args)
+  }
+  if _, args := b.BuildNamed(); args["name"] != "bar" {
+    t.Errorf("binding again changed an earlier binding: %v", args)
+  }
+}
+
+func TestPreparedBindErrors(t *testing.T) {
+  p := Prepare(NewQ().Select("uuid").From("foo").Filter(fooName.EqParam("name")))
+  if _, err := p.Bind(nil); err == nil {
+    t.Error("expected an error for an unbound parameter")
+  }
+  if _, err := p.Bind(map[string]any{"name": "bar", "age": 1}); err == nil {
+    t.Error("expected an error for an unknown parameter")
+  }
+}
+
+// end
@@ -0,0 +1,158 @@
+package goqdsl
+
+import (
+	"strings"
+)
+
+// clauses start a new line in ToSQLPretty. Longer keywords come first so
+// INNER JOIN is not broken before JOIN, nor INSERT INTO before INTO.
+var clauses = []string{
+  "SELECT", "INSERT INTO", "INTO", "FROM", "INNER JOIN", "LEFT JOIN", "RIGHT JOIN", "FULL JOIN", "JOIN",
+  "WHERE", "AND", "GROUP BY", "HAVING", "ORDER BY", "LIMIT", "OFFSET",
+  "VALUES", "ON CONFLICT", "DO UPDATE SET", "DO NOTHING",
+}
+
+// ToSQLPretty is ToSQL with every clause on its own line, ANDs aligned under
+// WHERE and subqueries indented. The AND of a range or BETWEEN stays inline.
+func ToSQLPretty(b Builder) string {
+  return pretty(ToSQL(b))
+}
+
+func pretty(sql string) string {
+
+  sql = collapse(sql)
+
+  var sb strings.Builder
+  var parens []bool // per open paren, whether a clause broke the line inside
+  indent := func() string { return strings.Repeat("  ", len(parens)) }
+  var pred strings.Builder // the predicate since the last clause keyword
+  between := false // a BETWEEN in pred still waits for its AND
+  pos := 0 // offset of the current token in sql
+
+  lex(sql, func(kind tokenKind, text string) {
+    start := pos
+    pos += len(text)
+    if kind == tokenParam {
+      text = "@" + text
+      pos++
+    }
+    if kind != tokenCode {
+      sb.WriteString(text)
+      pred.WriteString(text)
+      return
+    }
+
+    for i := 0; i < len(text); i++ {
+      c := text[i]
+      prev := byte(' ')
+      if i > 0 {
+        prev = text[i-1]
+      } else if sb.Len() > 0 {
+        prev = sb.String()[sb.Len()-1]
+      }
+
+      switch {
+      case c == '(':
+        sb.WriteByte(c)
+        pred.WriteByte(c)
+        parens = append(parens, false)
+
+      case c == ')':
+        if len(parens) > 0 {
+          broke := parens[len(parens)-1]
+          parens = parens[:len(parens)-1]
+          if broke {
+            sb.WriteString("\n" + indent())
+          }
+        }
+        sb.WriteByte(c)
+        pred.WriteByte(c)
+
+      default:
+        kw := clauseAt(text, i, prev)
+        if kw == "AND" && (between || rangeEnd(pred.String(), sql[start+i+len(kw):])) {
+          kw, between = "", false
+        }
+        if kw == "" {
+          if (prev == ' ' || prev == '(') && strings.HasPrefix(text[i:], "BETWEEN ") {
+            between = true
+          }
+          sb.WriteByte(c)
+          pred.WriteByte(c)
+          continue
+        }
+        if len(parens) > 0 {
+          parens[len(parens)-1] = true
+        }
+        out := strings.TrimRight(sb.String(), " ")
+        sb.Reset()
+        sb.WriteString(out)
+        if out != "" {
+          sb.WriteString("\n" + indent())
+        }
+        if kw == "AND" {
+          sb.WriteString("  ")
+        }
+        sb.WriteString(kw)
+        pred.Reset()
+        between = false
+        i += len(kw) - 1
+      }
+    }
+  })
+  return sb.String()
+}
+
+// rangeEnd reports whether an AND between pred and rest joins the two bounds
+// of a range as Cond writes it, column >= @from AND column < @to.
+func rangeEnd(pred, rest string) bool {
+  lhs, _, ok := strings.Cut(strings.TrimSpace(pred), " >= ")
+  return ok && strings.HasPrefix(strings.TrimLeft(rest, " "), lhs+" < ")
+}
+
+// clauseAt returns the clause keyword starting at sql[i], if it stands as a
+// word of its own; prev is the byte before it.
+func clauseAt(sql string, i int, prev byte) string {
+  if prev != ' ' && prev != '(' {
+    return ""
+  }
+  for _, kw := range clauses {
+    if !strings.HasPrefix(sql[i:], kw) {
+      continue
+    }
+    if end := i + len(kw); end == len(sql) || sql[end] == ' ' || sql[end] == '(' {
+      return kw
+    }
+  }
+  return ""
+}
+
+// collapse trims sql and reduces whitespace outside strings, quoted
+// identifiers and comments to single spaces.
+func collapse(sql string) string {
+  var sb strings.Builder
+  sb.Grow(len(sql))
+  lex(sql, func(kind tokenKind, text string) {
+    switch kind {
+    case tokenCode:
+      fields := strings.Fields(text)
+      if len(fields) > 0 && text[0] <= ' ' && sb.Len() > 0 {
+        sb.WriteByte(' ')
+      }
+      sb.WriteString(strings.Join(fields, " "))
+      if len(fields) > 0 && text[len(text)-1] <= ' ' {
+        sb.WriteByte(' ')
+      }
+      if len(fields) == 0 && sb.Len() > 0 {
+        sb.WriteByte(' ')
+      }
+    case tokenParam:
+      sb.WriteString("@" + text)
+    default:
+      sb.WriteString(text)
+    }
+  })
+  return strings.TrimSpace(sb.String())
+}
+
+// end
@@ -0,0 +1,76 @@
+package goqdsl
+
+import (
+	"testing"
+	"time"
+)
+
+func TestToSQLPretty(t *testing.T) {
+  q := NewQ().Select("uuid", "name").From("foo").Where(map[string]string{"name": "it's  FROM here", "uuid": "1"})
+
+  expected := "SELECT uuid, name\n" +
+    "FROM foo\n" +
+    "WHERE name = 'it''s  FROM here'\n" +
+    "  AND uuid = '1'"
+  if sql := ToSQLPretty(q); sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+}
+
+func TestPrettySubquery(t *testing.T) {
+  sql := pretty("SELECT COUNT(*) FROM (SELECT uuid, from_date FROM foo WHERE a = 1) AS count")
+
+  expected := "SELECT COUNT(*)\n" +
+    "FROM (\n" +
+    "  SELECT uuid, from_date\n" +
+    "  FROM foo\n" +
+    "  WHERE a = 1\n" +
+    ") AS count"
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+}
+
+func TestPrettyUpsert(t *testing.T) {
+  sql := ToSQLPretty(Upsert("foo", []string{"uuid", "name"}, "uuid").Row("1", "bar"))
+
+  expected := "INSERT INTO foo (uuid, name)\n" +
+    "VALUES ('1', 'bar')\n" +
+    "ON CONFLICT (uuid)\n" +
+    "DO UPDATE SET name = EXCLUDED.name"
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+}
+func TestPrettyRange(t *testing.T) {
+  from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+  q := NewQ().Select("uuid").From("events").Filter(BetweenTime("created", from, from.AddDate(0, 1, 0))).
+    Where(map[string]string{"kind": "a"})
+
+  sql, _ := q.BuildNamed()
+  expected := "SELECT uuid\n" +
+    "FROM events\n" +
+    "WHERE kind = @kind\n" +
+    "  AND created >= @created AND created < @created_2"
+  if sql := pretty(sql); sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+
+  expected = "SELECT a\nFROM t\nWHERE b BETWEEN 1 AND 2\n  AND c = 3"
+  if sql := pretty("SELECT a FROM t WHERE b BETWEEN 1 AND 2 AND c = 3"); sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+}
+
+func TestPrettyQuoted(t *testing.T) {
+  sql := pretty(`SELECT "a AND b", "FROM" FROM t /* WHERE x AND y */ WHERE c = @c`)
+
+  expected := "SELECT \"a AND b\", \"FROM\"\n" +
+    "FROM t /* WHERE x AND y */\n" +
+    "WHERE c = @c"
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+}
+
+// end
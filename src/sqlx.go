@@ -0,0 +1,27 @@
+package goqdsl
+
+import (
+	"strings"
+)
+
+// BuildSqlx renders b for sqlx.NamedQuery and NamedExec: parameters become
+// :name and the args map holds them by name. sqlx reads :: as an escaped
+// colon, so every other colon, as in casts, is doubled.
+func BuildSqlx(b Builder) (string, map[string]any) {
+
+  sql, args := b.BuildNamed()
+
+  var sb strings.Builder
+  sb.Grow(len(sql))
+  lex(sql, func(kind tokenKind, text string) {
+    if kind == tokenParam {
+      sb.WriteByte(':')
+      sb.WriteString(text)
+      return
+    }
+    sb.WriteString(strings.ReplaceAll(text, ":", "::"))
+  })
+  return sb.String(), args
+}
+
+// end
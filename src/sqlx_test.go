@@ -0,0 +1,29 @@
+package goqdsl
+
+import (
+	"testing"
+)
+
+func TestBuildSqlx(t *testing.T) {
+  q := NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "bar"}).Filter(EqEnum("status", "order_status", "paid"))
+
+  sql, args := BuildSqlx(q)
+  expected := "SELECT uuid FROM foo WHERE name = :name AND   status = :status::::order_status "
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+  if args["name"] != "bar" || args["status"] != "paid" {
+    t.Errorf("unexpected args: %v", args)
+  }
+}
+
+func TestBuildSqlxLiterals(t *testing.T) {
+  sql, _ := BuildSqlx(NewQ().SelectExpr(Unsafe("'a:b' AS c"), Unsafe("created::date")).From("foo"))
+
+  expected := "SELECT 'a::b' AS c, created::::date FROM foo "
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+}
+
+// end
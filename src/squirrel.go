@@ -0,0 +1,56 @@
+package goqdsl
+
+import (
+	"strings"
+)
+
+// Sqlizer adapts a builder to squirrel's Sqlizer interface, so it can be
+// passed where squirrel builders are taken, also nested in one. Parameters
+// become ? for squirrel to number. squirrel does not see string literals, so
+// every other ?, as in the jsonb ? operator, is escaped as ??.
+type Sqlizer struct {
+  b Builder
+}
+
+// SqlizerOf adapts any builder; Q, UpsertQ and Bound have a Sqlizer method.
+func SqlizerOf(b Builder) Sqlizer {
+  return Sqlizer{b: b}
+}
+
+func (q *Q) Sqlizer() Sqlizer {
+  return SqlizerOf(q)
+}
+
+func (u *UpsertQ) Sqlizer() Sqlizer {
+  return SqlizerOf(u)
+}
+
+func (b *Bound) Sqlizer() Sqlizer {
+  return SqlizerOf(b)
+}
+
+// ToSql renders the builder with ? placeholders. It never fails.
+func (s Sqlizer) ToSql() (string, []any, error) {
+
+  sql, named := s.b.BuildNamed()
+
+  var sb strings.Builder
+  sb.Grow(len(sql))
+  args := make([]any, 0, len(named))
+  lex(sql, func(kind tokenKind, text string) {
+    v, ok := named[text]
+    switch {
+    case kind == tokenParam && ok:
+      sb.WriteByte('?')
+      args = append(args, v)
+    case kind == tokenParam:
+      sb.WriteByte('@')
+      sb.WriteString(text)
+    default:
+      sb.WriteString(strings.ReplaceAll(text, "?", "??"))
+    }
+  })
+  return sb.String(), args, nil
+}
+
+// end
@@ -0,0 +1,39 @@
+package goqdsl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSqlizer(t *testing.T) {
+  q := NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "bar"}).Filter(Column[int]("age").In(30, 40))
+
+  // squirrel's interface, declared here to keep the dependency out.
+  var s interface{ ToSql() (string, []any, error) } = q.Sqlizer()
+  sql, args, err := s.ToSql()
+  if err != nil {
+    t.Fatal(err)
+  }
+  expected := "SELECT uuid FROM foo WHERE name = ? AND   age IN (?, ?) "
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+  if !reflect.DeepEqual(args, []any{"bar", 30, 40}) {
+    t.Errorf("unexpected args: %v", args)
+  }
+}
+
+func TestSqlizerEscapes(t *testing.T) {
+  q := NewQ().SelectExpr(Unsafe("data ? 'key' AS has_key"), Unsafe("'what?' AS q")).From("foo").Filter(Column[string]("name").Eq("bar"))
+
+  sql, args, _ := SqlizerOf(q).ToSql()
+  expected := "SELECT data ?? 'key' AS has_key, 'what??' AS q FROM foo WHERE name = ? "
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+  if len(args) != 1 || args[0] != "bar" {
+    t.Errorf("unexpected args: %v", args)
+  }
+}
+
+// end
@@ -0,0 +1,135 @@
+package goqdsl
+
+import (
+	"log/slog"
+)
+
+// Redacted shows a builder with placeholders instead of values, for logs that
+// must not contain user data.
+type Redacted struct {
+  b Builder
+}
+
+// Redact wraps b for logging, e.g. slog.Info("query", "sql", Redact(q)).
+func Redact(b Builder) Redacted {
+  return Redacted{b: b}
+}
+
+func (r Redacted) String() string {
+  sql, _ := r.b.BuildNamed()
+  return collapse(sql)
+}
+
+func (r Redacted) LogValue() slog.Value {
+  return slog.StringValue(r.String())
+}
+
+func debugSQL(b Builder) string {
+  return collapse(ToSQL(b))
+}
+
+func (q *Q) String() string {
+  return debugSQL(q)
+}
+
+func (q *Q) LogValue() slog.Value {
+  return slog.StringValue(debugSQL(q))
+}
+
+func (c *CreateTableQ) String() string {
+  return debugSQL(c)
+}
+
+func (c *CreateTableQ) LogValue() slog.Value {
+  return slog.StringValue(debugSQL(c))
+}
+
+func (c *CreateTableAsQ) String() string {
+  return debugSQL(c)
+}
+
+func (c *CreateTableAsQ) LogValue() slog.Value {
+  return slog.StringValue(debugSQL(c))
+}
+
+func (m *MaterializedViewQ) String() string {
+  return debugSQL(m)
+}
+
+func (m *MaterializedViewQ) LogValue() slog.Value {
+  return slog.StringValue(debugSQL(m))
+}
+
+func (r *RefreshQ) String() string {
+  return debugSQL(r)
+}
+
+func (r *RefreshQ) LogValue() slog.Value {
+  return slog.StringValue(debugSQL(r))
+}
+
+func (t *TruncateQ) String() string {
+  return debugSQL(t)
+}
+
+func (t *TruncateQ) LogValue() slog.Value {
+  return slog.StringValue(debugSQL(t))
+}
+
+func (p *PartitionQ) String() string {
+  return debugSQL(p)
+}
+
+func (p *PartitionQ) LogValue() slog.Value {
+  return slog.StringValue(debugSQL(p))
+}
+
+func (d *DetachPartitionQ) String() string {
+  return debugSQL(d)
+}
+
+func (d *DetachPartitionQ) LogValue() slog.Value {
+  return slog.StringValue(debugSQL(d))
+}
+
+func (d *DropTableQ) String() string {
+  return debugSQL(d)
+}
+
+func (d *DropTableQ) LogValue() slog.Value {
+  return slog.StringValue(debugSQL(d))
+}
+
+func (b *Bound) String() string {
+  return debugSQL(b)
+}
+
+func (b *Bound) LogValue() slog.Value {
+  return slog.StringValue(debugSQL(b))
+}
+
+func (g *GrantQ) String() string {
+  return debugSQL(g)
+}
+
+func (g *GrantQ) LogValue() slog.Value {
+  return slog.StringValue(debugSQL(g))
+}
+
+func (n *NotifyQ) String() string {
+  return debugSQL(n)
+}
+
+func (n *NotifyQ) LogValue() slog.Value {
+  return slog.StringValue(debugSQL(n))
+}
+
+func (u *UpsertQ) String() string {
+  return debugSQL(u)
+}
+
+func (u *UpsertQ) LogValue() slog.Value {
+  return slog.StringValue(debugSQL(u))
+}
+
+// end
@@ -0,0 +1,53 @@
+package goqdsl
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+var (
+  _ fmt.Stringer = (*Q)(nil)
+  _ slog.LogValuer = (*Q)(nil)
+  _ slog.LogValuer = (*CreateTableQ)(nil)
+  _ slog.LogValuer = (*CreateTableAsQ)(nil)
+  _ slog.LogValuer = (*MaterializedViewQ)(nil)
+  _ slog.LogValuer = (*RefreshQ)(nil)
+  _ slog.LogValuer = (*GrantQ)(nil)
+  _ slog.LogValuer = (*NotifyQ)(nil)
+  _ slog.LogValuer = (*UpsertQ)(nil)
+  _ slog.LogValuer = Redacted{}
+)
+
+func TestString(t *testing.T) {
+  q := NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "bar"})
+  if s := fmt.Sprint(q); s != "SELECT uuid FROM foo WHERE name = 'bar'" {
+    t.Errorf("unexpected string: %s", s)
+  }
+
+  var buf bytes.Buffer
+  slog.New(slog.NewTextHandler(&buf, nil)).Info("query", "sql", q)
+  if !strings.Contains(buf.String(), `sql="SELECT uuid FROM foo WHERE name = 'bar'"`) {
+    t.Errorf("unexpected log: %s", buf.String())
+  }
+}
+
+func TestRedact(t *testing.T) {
+  q := NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "secret"})
+  if s := Redact(q).String(); s != "SELECT uuid FROM foo WHERE name = @name" {
+    t.Errorf("unexpected string: %s", s)
+  }
+  if s := q.String(); !strings.Contains(s, "secret") {
+    t.Errorf("expected Redact to leave q alone, got %s", s)
+  }
+
+  var buf bytes.Buffer
+  slog.New(slog.NewTextHandler(&buf, nil)).Info("query", "sql", Redact(q))
+  if strings.Contains(buf.String(), "secret") {
+    t.Errorf("unexpected log: %s", buf.String())
+  }
+}
+
+// end
@@ -0,0 +1,51 @@
+package goqdsl
+
+// Table names a table and its alias once, for From and joins:
+//
+//  users, orders := T("users").As("u"), T("orders").As("o")
+//  NewQ().Select(users.Col("name"), orders.Col("total")).FromTable(users).
+//    InnerJoin([]Join{orders.On(users.Col("uuid"), orders.Col("user_uuid"))})
+type Table struct {
+  name string
+  alias string
+}
+
+func T(name string) Table {
+  return Table{name: name}
+}
+
+func (t Table) As(alias string) Table {
+  t.alias = alias
+  return t
+}
+
+func (t Table) Name() string {
+  return t.name
+}
+
+func (t Table) Alias() string {
+  return t.alias
+}
+
+// Col qualifies column with the alias, or the table name without one.
+func (t Table) Col(column string) string {
+  if t.alias != "" {
+    return t.alias + "." + column
+  }
+  return t.name + "." + column
+}
+
+// On joins t where left equals right.
+func (t Table) On(left, right string) Join {
+  return Join{table: t.String(), left: left, right: right}
+}
+
+// String is the table as it appears after FROM or JOIN.
+func (t Table) String() string {
+  if t.alias != "" {
+    return t.name + " " + t.alias
+  }
+  return t.name
+}
+
+// end
@@ -0,0 +1,39 @@
+package goqdsl
+
+import (
+	"testing"
+)
+
+func TestTable(t *testing.T) {
+  users, orders := T("users").As("u"), T("orders").As("o")
+  q := NewQ().Select(users.Col("name"), orders.Col("total")).FromTable(users).
+    InnerJoin([]Join{orders.On(users.Col("uuid"), orders.Col("user_uuid"))}).
+    Where(map[string]string{users.Col("name"): "bar"})
+
+  sql, args := q.BuildNamed()
+  expected := "SELECT u.name, o.total FROM users u INNER JOIN orders o ON u.uuid = o.user_uuid WHERE u.name = @u_name "
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+  if args["u_name"] != "bar" {
+    t.Errorf("unexpected args: %v", args)
+  }
+  if q.Table() != "users" || q.Alias() != "u" {
+    t.Errorf("expected users u, got %s %s", q.Table(), q.Alias())
+  }
+  if err := CheckIdentifiers(q); err != nil {
+    t.Error(err)
+  }
+}
+
+func TestTableWithoutAlias(t *testing.T) {
+  users := T("users")
+  if c := users.Col("name"); c != "users.name" {
+    t.Errorf("expected users.name, got %s", c)
+  }
+  if s := users.String(); s != "users" {
+    t.Errorf("expected users, got %s", s)
+  }
+}
+
+// end
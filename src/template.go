@@ -0,0 +1,76 @@
+package goqdsl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+  templateVar = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+  templateValue = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+)
+
+// Template returns pattern, a table name with {{name}} placeholders such as
+// "events_{{shard}}", for From, FromTable or joins. The placeholders are
+// bound with Bind, or by goqdslpgx from the context; Bind fails if pattern is
+// not a table name once the placeholders are filled in.
+func Template(pattern string) string {
+  return pattern
+}
+
+// Bind returns a copy of q with the placeholders in its table names replaced
+// by vars, or q itself if it has none. Values may only hold letters, digits
+// and underscores, and the bound names must pass CheckIdentifiers.
+func (q *Q) Bind(vars map[string]string) (*Q, error) {
+
+  if !q.templated() {
+    return q, nil
+  }
+
+  var err error
+  bind := func(s string, re *regexp.Regexp) string {
+    if !strings.Contains(s, "{{") {
+      return s
+    }
+    s = templateVar.ReplaceAllStringFunc(s, func(m string) string {
+      name := templateVar.FindStringSubmatch(m)[1]
+      v, ok := vars[name]
+      if err == nil && !ok {
+        err = fmt.Errorf("goqdsl: no value for {{%s}} in %s", name, s)
+      } else if err == nil && !templateValue.MatchString(v) {
+        err = fmt.Errorf("goqdsl: invalid value %q for {{%s}}", v, name)
+      }
+      return v
+    })
+    if err == nil && !re.MatchString(s) {
+      err = fmt.Errorf("goqdsl: invalid table template %q", s)
+    }
+    return s
+  }
+
+  c := q.Clone()
+  c.from = bind(c.from, qualifiedIdent)
+  c.into = bind(c.into, qualifiedIdent)
+  for i := range c.joins {
+    c.joins[i].table = bind(c.joins[i].table, tableRef)
+  }
+  if err != nil {
+    return nil, err
+  }
+  return c, nil
+}
+
+func (q *Q) templated() bool {
+  if strings.Contains(q.from, "{{") || strings.Contains(q.into, "{{") {
+    return true
+  }
+  for _, j := range q.joins {
+    if strings.Contains(j.table, "{{") {
+      return true
+    }
+  }
+  return false
+}
+
+// end
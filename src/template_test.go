@@ -0,0 +1,47 @@
+package goqdsl
+
+import (
+	"testing"
+)
+
+func TestBind(t *testing.T) {
+  q := NewQ().Select("uuid").From(Template("events_{{shard}}")).
+    InnerJoin([]Join{T(Template("sessions_{{ shard }}")).As("s").On("events_uuid", "s.event_uuid")}).Freeze()
+
+  bound, err := q.Bind(map[string]string{"shard": "07"})
+  if err != nil {
+    t.Fatal(err)
+  }
+  expected := "SELECT uuid FROM events_07 INNER JOIN sessions_07 s ON events_uuid = s.event_uuid "
+  if sql := bound.Query(); sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+  if q.Table() != "events_{{shard}}" {
+    t.Errorf("Bind changed q: %s", q.Table())
+  }
+
+  if _, err := q.Bind(nil); err == nil {
+    t.Error("expected an error for a missing value")
+  }
+  if _, err := q.Bind(map[string]string{"shard": "1; DROP TABLE events"}); err == nil {
+    t.Error("expected an error for an invalid value")
+  }
+
+  plain := NewQ().Select("uuid").From("foo")
+  if b, err := plain.Bind(nil); err != nil || b != plain {
+    t.Errorf("expected q itself without placeholders, got %v (%v)", b, err)
+  }
+}
+
+func TestTemplateInvalid(t *testing.T) {
+  for _, q := range []*Q{
+    NewQ().Select("uuid").From(Template("events {{shard}}")),
+    NewQ().Select("uuid").From("events").InnerJoin([]Join{T(Template("sessions_{{shard}}; DROP")).On("uuid", "event_uuid")}),
+  } {
+    if _, err := q.Bind(map[string]string{"shard": "07"}); err == nil {
+      t.Errorf("%s: expected an invalid template error", q.Query())
+    }
+  }
+}
+
+// end
@@ -0,0 +1,63 @@
+package goqdsl
+
+import (
+	"fmt"
+	"time"
+)
+
+// BetweenTime matches column from from up to, but not including, to. Both
+// bind as instants, so for timestamptz columns the zones of from and to do
+// not matter.
+func BetweenTime(column string, from, to time.Time) Cond {
+  return Cond{column: column, op: "RANGE", values: []any{from, to}}
+}
+
+// OnDate matches column during the calendar day of date in loc, from its
+// midnight up to the next, which is not always 24 hours later.
+func OnDate(column string, date time.Time, loc *time.Location) Cond {
+  y, m, d := date.In(loc).Date()
+  return BetweenTime(column, time.Date(y, m, d, 0, 0, 0, 0, loc), time.Date(y, m, d+1, 0, 0, 0, 0, loc))
+}
+
+// AtTimeZone compares the column as column AT TIME ZONE zone, for timestamp
+// without time zone columns holding wall-clock times in zone, e.g. 'UTC'.
+// This turns them into instants, comparable with bound time.Time values.
+func (c Cond) AtTimeZone(zone string) Cond {
+  c.zone = zone
+  return c
+}
+
+// bucketUnits are the units date_trunc takes.
+var bucketUnits = map[string]bool{
+  "microseconds": true, "milliseconds": true, "second": true, "minute": true,
+  "hour": true, "day": true, "week": true, "month": true, "quarter": true,
+  "year": true, "decade": true, "century": true, "millennium": true,
+}
+
+// TimeBucket renders date_trunc(unit, column), the start of the unit holding
+// column. With a zone the column is truncated as column AT TIME ZONE zone, so
+// days of a timestamptz column start at midnight in zone; the bucket is then a
+// wall-clock time in zone. An unknown unit, e.g. from a report request, is
+// quoted as given and fails CheckIdentifiers.
+func TimeBucket(column, unit string, zone ...string) Expr {
+  var err error
+  if !bucketUnits[unit] {
+    err = fmt.Errorf("goqdsl: unknown time bucket unit %q", unit)
+  }
+  expr := column
+  if len(zone) > 0 {
+    expr += " AT TIME ZONE " + quote(zone[0])
+  }
+  return trusted("date_trunc("+quote(unit)+", "+expr+")", err, column)
+}
+
+// GroupByTimeBucket selects TimeBucket(column, unit, zone...) AS bucket and
+// groups by it, for rollups:
+//
+//  NewQ().Select("count(*) AS n").From("orders").GroupByTimeBucket("created", "day", "Europe/Amsterdam").OrderBy(Asc("bucket"))
+func (q *Q) GroupByTimeBucket(column, unit string, zone ...string) *Q {
+  bucket := TimeBucket(column, unit, zone...)
+  return q.SelectExpr(As(bucket, "bucket")).GroupByExpr(bucket)
+}
+
+// end
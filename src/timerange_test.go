@@ -0,0 +1,74 @@
+package goqdsl
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBetweenTime(t *testing.T) {
+  from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+  to := from.AddDate(0, 1, 0)
+
+  sql, args := NewQ().Select("uuid").From("events").Filter(BetweenTime("created", from, to)).BuildNamed()
+  expected := "SELECT uuid FROM events WHERE created >= @created AND   created < @created_2 "
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+  if args["created"] != from || args["created_2"] != to {
+    t.Errorf("unexpected args: %v", args)
+  }
+}
+
+func TestOnDate(t *testing.T) {
+  amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+  if err != nil {
+    t.Skip(err)
+  }
+
+  // 23:30 UTC on the 30th is already the 31st in Amsterdam, which has 23
+  // hours as summer time starts.
+  c := OnDate("created", time.Date(2024, 3, 30, 23, 30, 0, 0, time.UTC), amsterdam)
+  from, to := c.values[0].(time.Time), c.values[1].(time.Time)
+  if !from.Equal(time.Date(2024, 3, 30, 23, 0, 0, 0, time.UTC)) || to.Sub(from) != 23*time.Hour {
+    t.Errorf("unexpected range %v - %v", from, to)
+  }
+
+  sql, _ := NewQ().Select("uuid").From("events").Filter(c.AtTimeZone("UTC")).BuildPositional()
+  expected := "SELECT uuid FROM events WHERE created AT TIME ZONE 'UTC' >= $1 AND   created AT TIME ZONE 'UTC' < $2 "
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+}
+
+func TestGroupByTimeBucket(t *testing.T) {
+  q := NewQ().Select("count(*) AS n").From("orders").GroupByTimeBucket("created", "day", "Europe/Amsterdam").OrderBy(Asc("bucket"))
+
+  expected := "SELECT count(*) AS n, date_trunc('day', created AT TIME ZONE 'Europe/Amsterdam') AS bucket FROM orders " +
+    "GROUP BY date_trunc('day', created AT TIME ZONE 'Europe/Amsterdam') ORDER BY bucket ASC "
+  if sql := q.Query(); sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+  if err := CheckIdentifiers(q); err != nil {
+    t.Error(err)
+  }
+
+  if b := TimeBucket("o.created", "hour").String(); b != "date_trunc('hour', o.created)" {
+    t.Errorf("unexpected bucket: %s", b)
+  }
+  if err := CheckIdentifiers(NewQ().Select("n").From("orders").GroupByTimeBucket("created; DROP TABLE orders", "day")); err == nil {
+    t.Error("expected an identifier error")
+  }
+}
+
+func TestTimeBucketUnit(t *testing.T) {
+  q := NewQ().Select("count(*) AS n").From("orders").GroupByTimeBucket("created", "fort'night")
+  if err := CheckIdentifiers(q); err == nil || !strings.Contains(err.Error(), "unknown time bucket unit") {
+    t.Errorf("expected an unknown unit error, got %v", err)
+  }
+  if b := TimeBucket("created", "fort'night").String(); b != "date_trunc('fort''night', created)" {
+    t.Errorf("expected the unit quoted, got %s", b)
+  }
+}
+
+// end
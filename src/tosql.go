@@ -0,0 +1,118 @@
+package goqdsl
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ToSQL renders b with its named parameters replaced by SQL literals, for
+// logs and debugging. Execute the BuildNamed output instead.
+func ToSQL(b Builder) string {
+
+  sql, args := b.BuildNamed()
+
+  var sb strings.Builder
+  sb.Grow(len(sql))
+  lex(sql, func(kind tokenKind, text string) {
+    if kind != tokenParam {
+      sb.WriteString(text)
+      return
+    }
+    if v, ok := args[text]; ok {
+      sb.WriteString(formatValue(v))
+    } else {
+      sb.WriteString("@" + text)
+    }
+  })
+  return sb.String()
+}
+
+// writeValue writes formatValue(v) to sb, directly for the common strings,
+// integers and bools.
+func writeValue(sb *strings.Builder, v any) {
+  var buf [20]byte
+  switch v := v.(type) {
+  case string:
+    if strings.ContainsAny(v, `'\`) {
+      sb.WriteString(quote(v))
+      return
+    }
+    sb.WriteByte('\'')
+    sb.WriteString(v)
+    sb.WriteByte('\'')
+  case int:
+    sb.Write(strconv.AppendInt(buf[:0], int64(v), 10))
+  case int64:
+    sb.Write(strconv.AppendInt(buf[:0], v, 10))
+  case int32:
+    sb.Write(strconv.AppendInt(buf[:0], int64(v), 10))
+  case bool:
+    sb.WriteString(strconv.FormatBool(v))
+  default:
+    sb.WriteString(formatValue(v))
+  }
+}
+
+// formatValue renders v as an SQL literal that cannot end early, whatever
+// standard_conforming_strings is set to: strings with backslashes use E''
+// syntax, bytes are hex decoded, and anything that is not a number or bool is
+// quoted.
+func formatValue(v any) string {
+
+  rv := reflect.ValueOf(v)
+  if v == nil || rv.Kind() == reflect.Pointer && rv.IsNil() {
+    return "NULL"
+  }
+
+  switch v := v.(type) {
+  case string:
+    return quote(v)
+  case bool:
+    return strconv.FormatBool(v)
+  case time.Time:
+    return quote(v.Format(time.RFC3339Nano))
+  case []byte:
+    return "decode('" + hex.EncodeToString(v) + "', 'hex')"
+  case fmt.Stringer:
+    return quote(v.String())
+  }
+
+  switch rv.Kind() {
+  case reflect.Pointer:
+    return formatValue(rv.Elem().Interface())
+  case reflect.Slice, reflect.Array:
+    if rv.Kind() == reflect.Slice && rv.IsNil() {
+      return "NULL"
+    }
+    if rv.Len() == 0 {
+      return "'{}'"
+    }
+    elems := make([]string, rv.Len())
+    for i := range elems {
+      elems[i] = formatValue(rv.Index(i).Interface())
+    }
+    return "ARRAY[" + strings.Join(elems, ", ") + "]"
+  case reflect.String:
+    return quote(rv.String())
+  case reflect.Bool:
+    return strconv.FormatBool(rv.Bool())
+  case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+    return strconv.FormatInt(rv.Int(), 10)
+  case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+    return strconv.FormatUint(rv.Uint(), 10)
+  case reflect.Float32, reflect.Float64:
+    f := rv.Float()
+    if math.IsNaN(f) || math.IsInf(f, 0) {
+      return quote(strconv.FormatFloat(f, 'g', -1, 64))
+    }
+    return strconv.FormatFloat(f, 'g', -1, rv.Type().Bits())
+  }
+  return quote(fmt.Sprint(v))
+}
+
+// end
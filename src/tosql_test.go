@@ -0,0 +1,128 @@
+package goqdsl
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+type args map[string]any
+
+// raw is a Builder over fixed SQL and args.
+type raw struct {
+  sql string
+  args args
+}
+
+func (r raw) Query() string {
+  return r.sql
+}
+
+func (r raw) BuildNamed() (string, map[string]any) {
+  return r.sql, r.args
+}
+
+func (r raw) BuildPositional() (string, []any) {
+  return r.sql, nil
+}
+
+func TestToSQL(t *testing.T) {
+  sql := ToSQL(NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "it's"}))
+  if sql != "SELECT uuid FROM foo WHERE name = 'it''s' " {
+    t.Errorf("unexpected sql: %s", sql)
+  }
+}
+
+func TestToSQLNames(t *testing.T) {
+  sql := ToSQL(raw{"SELECT @p1, @p10, @p1x, @missing, a@", args{"p1": 1, "p10": "@p1", "p1x": nil}})
+  expected := "SELECT 1, '@p1', NULL, @missing, a@"
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+}
+
+func TestFormatValue(t *testing.T) {
+  name := "bar"
+  var none *string
+  for _, c := range []struct {
+    value any
+    expected string
+  }{
+    {time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), "'2024-03-01T12:00:00Z'"},
+    {[]byte{0xde, 0xad}, "decode('dead', 'hex')"},
+    {`a\' OR 1=1`, `E'a\\'' OR 1=1'`},
+    {[]int{}, "'{}'"},
+    {math.NaN(), "'NaN'"},
+    {struct{ A string }{"x'"}, "'{x''}'"},
+    {map[string]int{"a": 1}, "'map[a:1]'"},
+    {int8(-3), "-3"},
+    {float32(0.1), "0.1"},
+    {[]int{1, 2}, "ARRAY[1, 2]"},
+    {[]string{"a", "b'c"}, "ARRAY['a', 'b''c']"},
+    {[]string(nil), "NULL"},
+    {&name, "'bar'"},
+    {none, "NULL"},
+    {true, "true"},
+    {3.5, "3.5"},
+  } {
+    if got := formatValue(c.value); got != c.expected {
+      t.Errorf("%#v: expected %s, got %s", c.value, c.expected, got)
+    }
+  }
+}
+
+// literalToken checks that sql is code followed by exactly one string
+// literal, so a value rendered at its end cannot have broken out of it.
+func literalToken(t *testing.T, sql string) {
+  var kinds []tokenKind
+  lex(sql, func(kind tokenKind, text string) { kinds = append(kinds, kind) })
+  if len(kinds) != 2 || kinds[0] != tokenCode || kinds[1] != tokenString {
+    t.Fatalf("value broke out of its literal: %s", sql)
+  }
+}
+
+func FuzzToSQLString(f *testing.F) {
+  for _, seed := range []string{"", "'", "''", `\'`, `\`, "'; DROP TABLE foo; --", "$$", "@v", "E'", "\x00"} {
+    f.Add(seed)
+  }
+  f.Fuzz(func(t *testing.T, s string) {
+    literalToken(t, ToSQL(raw{"SELECT @v", args{"v": s}}))
+  })
+}
+
+func FuzzToSQLBytes(f *testing.F) {
+  f.Add([]byte("'\\"))
+  f.Fuzz(func(t *testing.T, b []byte) {
+    sql := ToSQL(raw{"SELECT @v", args{"v": b}})
+    if sql != "SELECT decode('"+hexString(b)+"', 'hex')" {
+      t.Fatalf("unexpected sql: %s", sql)
+    }
+  })
+}
+
+func FuzzWriteValue(f *testing.F) {
+  for _, seed := range []string{"", "bar", "'", `\`, "'; DROP TABLE foo; --"} {
+    f.Add(seed, int64(42))
+  }
+  f.Fuzz(func(t *testing.T, s string, n int64) {
+    for _, v := range []any{s, n, int(n), int32(n), n > 0, float64(n), &s} {
+      var sb strings.Builder
+      writeValue(&sb, v)
+      if sb.String() != formatValue(v) {
+        t.Fatalf("writeValue(%#v) = %s, formatValue gives %s", v, sb.String(), formatValue(v))
+      }
+    }
+  })
+}
+
+func hexString(b []byte) string {
+  const digits = "0123456789abcdef"
+  out := make([]byte, 0, 2*len(b))
+  for _, c := range b {
+    out = append(out, digits[c>>4], digits[c&15])
+  }
+  return string(out)
+}
+
+// end
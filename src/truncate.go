@@ -0,0 +1,54 @@
+package goqdsl
+
+import (
+	"strings"
+)
+
+// TruncateQ empties tables at once, faster than a DELETE without WHERE. Its
+// table names are checked by CheckIdentifiers like those of the other DDL
+// builders.
+type TruncateQ struct {
+  tables []string
+  restartIdentity bool
+  cascade bool
+}
+
+// Truncate empties tables in one statement, e.g. Truncate("orders",
+// "order_lines").Cascade().
+func Truncate(tables ...string) *TruncateQ {
+  return &TruncateQ{tables: tables}
+}
+
+// RestartIdentity resets the sequences owned by the tables' columns.
+func (t *TruncateQ) RestartIdentity() *TruncateQ {
+  t.restartIdentity = true
+  return t
+}
+
+// Cascade also truncates tables with foreign keys to these.
+func (t *TruncateQ) Cascade() *TruncateQ {
+  t.cascade = true
+  return t
+}
+
+func (t *TruncateQ) BuildNamed() (string, map[string]any) {
+  return t.Query(), nil
+}
+
+func (t *TruncateQ) BuildPositional() (string, []any) {
+  return t.Query(), nil
+}
+
+func (t *TruncateQ) Query() string {
+
+  sql := "TRUNCATE " + strings.Join(t.tables, ", ")
+  if t.restartIdentity {
+    sql += " RESTART IDENTITY"
+  }
+  if t.cascade {
+    sql += " CASCADE"
+  }
+  return sql
+}
+
+// end
@@ -0,0 +1,19 @@
+package goqdsl
+
+import (
+	"testing"
+)
+
+func TestTruncate(t *testing.T) {
+  if sql := Truncate("foo", "bar").Query(); sql != "TRUNCATE foo, bar" {
+    t.Errorf("unexpected sql: %s", sql)
+  }
+  if sql := Truncate("foo").RestartIdentity().Cascade().Query(); sql != "TRUNCATE foo RESTART IDENTITY CASCADE" {
+    t.Errorf("unexpected sql: %s", sql)
+  }
+  if err := CheckIdentifiers(Truncate("public.foo", `"Bar"`)); err != nil {
+    t.Errorf("expected qualified and quoted tables to pass, got %v", err)
+  }
+}
+
+// end
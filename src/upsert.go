@@ -0,0 +1,137 @@
+package goqdsl
+
+import (
+	"strconv"
+	"strings"
+)
+
+// UpsertQ renders INSERT ... ON CONFLICT (keys) DO UPDATE for the columns
+// that are not keys, or DO NOTHING when all columns are keys.
+type UpsertQ struct {
+  table string
+  columns []string
+  keys []string
+  rows [][]any
+}
+
+func Upsert(table string, columns []string, keys ...string) *UpsertQ {
+  return &UpsertQ{table: table, columns: columns, keys: keys}
+}
+
+func (u *UpsertQ) Table() string {
+  return u.table
+}
+
+// Row adds a row, values in the order of the columns.
+func (u *UpsertQ) Row(values ...any) *UpsertQ {
+  u.rows = append(u.rows, values)
+  return u
+}
+
+func (u *UpsertQ) Query() string {
+  return u.build(func(sb *strings.Builder, row, col int, v any) { sb.WriteString(formatValue(v)) })
+}
+
+// BuildNamed names parameters after their column and row, e.g. @name_0. A
+// name that is taken, e.g. by t.a and t_a, gets a _2, _3, ... suffix as in
+// Q.BuildNamed.
+func (u *UpsertQ) BuildNamed() (string, map[string]any) {
+  args := make(map[string]any, len(u.rows)*len(u.columns))
+  prefixes := make([]string, len(u.columns))
+  for i, col := range u.columns {
+    prefixes[i] = paramName(col) + "_"
+  }
+  var buf []byte
+  sql := u.build(func(sb *strings.Builder, row, col int, v any) {
+    buf = strconv.AppendInt(append(buf[:0], prefixes[col]...), int64(row), 10)
+    name := string(buf)
+    for i := 2; ; i++ {
+      if _, taken := args[name]; !taken {
+        break
+      }
+      name = string(buf) + "_" + strconv.Itoa(i)
+    }
+    args[name] = v
+    sb.WriteByte('@')
+    sb.WriteString(name)
+  })
+  return sql, args
+}
+
+func (u *UpsertQ) BuildPositional() (string, []any) {
+  args := make([]any, 0, len(u.rows)*len(u.columns))
+  sql := u.build(func(sb *strings.Builder, row, col int, v any) {
+    args = append(args, v)
+    writePositional(sb, len(args))
+  })
+  return sql, args
+}
+
+func (u *UpsertQ) build(value func(sb *strings.Builder, row, col int, v any)) string {
+
+  var sb strings.Builder
+  sb.Grow(64 + len(u.table) + len(u.rows)*len(u.columns)*12 + len(u.columns)*32)
+
+  sb.WriteString("INSERT INTO ")
+  sb.WriteString(u.table)
+  sb.WriteString(" (")
+  sb.WriteString(strings.Join(u.columns, ", "))
+  sb.WriteString(") VALUES ")
+
+  for i, row := range u.rows {
+    if i > 0 {
+      sb.WriteString(", ")
+    }
+    sb.WriteByte('(')
+    for j := range u.columns {
+      if j > 0 {
+        sb.WriteString(", ")
+      }
+      var v any
+      if j < len(row) {
+        v = row[j]
+      }
+      value(&sb, i, j, v)
+    }
+    sb.WriteByte(')')
+  }
+
+  if len(u.keys) == 0 {
+    return sb.String()
+  }
+
+  sb.WriteString(" ON CONFLICT (")
+  sb.WriteString(strings.Join(u.keys, ", "))
+  sb.WriteString(") ")
+
+  set := 0
+  for _, col := range u.columns {
+    if isKey(u.keys, col) {
+      continue
+    }
+    if set == 0 {
+      sb.WriteString("DO UPDATE SET ")
+    } else {
+      sb.WriteString(", ")
+    }
+    sb.WriteString(col)
+    sb.WriteString(" = EXCLUDED.")
+    sb.WriteString(col)
+    set++
+  }
+  if set == 0 {
+    sb.WriteString("DO NOTHING")
+  }
+  return sb.String()
+}
+
+func isKey(keys []string, col string) bool {
+  for _, k := range keys {
+    if k == col {
+      return true
+    }
+  }
+  return false
+}
+
+// end
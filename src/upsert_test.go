@@ -0,0 +1,60 @@
+package goqdsl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUpsert(t *testing.T) {
+  q := Upsert("foo", []string{"uuid", "name"}, "uuid").Row("1", "bar").Row("2", "it's")
+
+  expected := "INSERT INTO foo (uuid, name) VALUES ('1', 'bar'), ('2', 'it''s') " +
+    "ON CONFLICT (uuid) DO UPDATE SET name = EXCLUDED.name"
+  if sql := q.Query(); sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+
+  sql, args := q.BuildNamed()
+  expected = "INSERT INTO foo (uuid, name) VALUES (@uuid_0, @name_0), (@uuid_1, @name_1) " +
+    "ON CONFLICT (uuid) DO UPDATE SET name = EXCLUDED.name"
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+  if args["name_1"] != "it's" || len(args) != 4 {
+    t.Errorf("unexpected args: %v", args)
+  }
+
+  sql, pargs := q.BuildPositional()
+  if sql != "INSERT INTO foo (uuid, name) VALUES ($1, $2), ($3, $4) ON CONFLICT (uuid) DO UPDATE SET name = EXCLUDED.name" {
+    t.Errorf("unexpected sql: %s", sql)
+  }
+  if !reflect.DeepEqual(pargs, []any{"1", "bar", "2", "it's"}) {
+    t.Errorf("unexpected args: %v", pargs)
+  }
+}
+
+func TestUpsertNamedCollision(t *testing.T) {
+  sql, args := Upsert("foo", []string{"t.a", "t_a"}).Row(1, 2).BuildNamed()
+  if sql != "INSERT INTO foo (t.a, t_a) VALUES (@t_a_0, @t_a_0_2)" {
+    t.Errorf("unexpected sql: %s", sql)
+  }
+  if !reflect.DeepEqual(args, map[string]any{"t_a_0": 1, "t_a_0_2": 2}) {
+    t.Errorf("unexpected args: %v", args)
+  }
+}
+
+func TestUpsertDoNothing(t *testing.T) {
+  sql := Upsert("tags", []string{"name"}, "name").Row("x").Query()
+  if sql != "INSERT INTO tags (name) VALUES ('x') ON CONFLICT (name) DO NOTHING" {
+    t.Errorf("unexpected sql: %s", sql)
+  }
+}
+
+func TestModelValues(t *testing.T) {
+  values := ModelValues(user{audit: audit{Created: "today"}, Uuid: "1", FullName: "bar", Password: "x"})
+  if !reflect.DeepEqual(values, []any{"today", "1", "bar"}) {
+    t.Errorf("unexpected values: %v", values)
+  }
+}
+
+// end
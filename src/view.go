@@ -0,0 +1,101 @@
+package goqdsl
+
+type MaterializedViewQ struct {
+  name string
+  query *Q
+  ifNotExists bool
+  withNoData bool
+}
+
+func CreateMaterializedView(name string, q *Q) *MaterializedViewQ {
+  return &MaterializedViewQ{name: name, query: q}
+}
+
+// Source returns the query the view is defined by.
+func (m *MaterializedViewQ) Source() *Q {
+  return m.query
+}
+
+// WithSource returns a copy of m defined by q instead.
+func (m *MaterializedViewQ) WithSource(q *Q) *MaterializedViewQ {
+  n := *m
+  n.query = q
+  return &n
+}
+
+func (m *MaterializedViewQ) IfNotExists() *MaterializedViewQ {
+  m.ifNotExists = true
+  return m
+}
+
+func (m *MaterializedViewQ) WithNoData() *MaterializedViewQ {
+  m.withNoData = true
+  return m
+}
+
+// Like CREATE TABLE AS, CREATE MATERIALIZED VIEW takes no bind parameters, so
+// the values of the query are rendered as quoted literals.
+func (m *MaterializedViewQ) BuildNamed() (string, map[string]any) {
+  return m.Query(), nil
+}
+
+func (m *MaterializedViewQ) BuildPositional() (string, []any) {
+  return m.Query(), nil
+}
+
+func (m *MaterializedViewQ) Query() string {
+
+  sql := "CREATE MATERIALIZED VIEW "
+  if m.ifNotExists {
+    sql += "IF NOT EXISTS "
+  }
+  sql += m.name + " AS " + inlined(m.query)
+  if m.withNoData {
+    sql += " WITH NO DATA"
+  }
+  return sql
+}
+
+type RefreshQ struct {
+  name string
+  concurrently bool
+  withNoData bool
+}
+
+func RefreshMaterializedView(name string) *RefreshQ {
+  return &RefreshQ{name: name}
+}
+
+// Concurrently requires a unique index on the view.
+func (r *RefreshQ) Concurrently() *RefreshQ {
+  r.concurrently = true
+  return r
+}
+
+func (r *RefreshQ) WithNoData() *RefreshQ {
+  r.withNoData = true
+  return r
+}
+
+func (r *RefreshQ) BuildNamed() (string, map[string]any) {
+  return r.Query(), nil
+}
+
+func (r *RefreshQ) BuildPositional() (string, []any) {
+  return r.Query(), nil
+}
+
+func (r *RefreshQ) Query() string {
+
+  sql := "REFRESH MATERIALIZED VIEW "
+  if r.concurrently {
+    sql += "CONCURRENTLY "
+  }
+  sql += r.name
+  if r.withNoData {
+    sql += " WITH NO DATA"
+  }
+  return sql
+}
+
+// end
@@ -0,0 +1,32 @@
+package goqdsl
+
+import (
+	"testing"
+)
+
+func TestCreateMaterializedView(t *testing.T) {
+  sql := CreateMaterializedView("foo_names", NewQ().Select("uuid", "name").From("foo")).
+    IfNotExists().
+    Query()
+
+  expected := "CREATE MATERIALIZED VIEW IF NOT EXISTS foo_names AS SELECT uuid, name FROM foo"
+  if sql != expected {
+    t.Errorf("expected:\n%s\ngot:\n%s", expected, sql)
+  }
+
+  q := NewQ().Select("uuid").From("foo").Where(map[string]string{"name": "o'brien"}).Filter(Column[string]("kind").Eq("it's"))
+  sql = CreateMaterializedView("foo_names", q).Query()
+  expected = "CREATE MATERIALIZED VIEW foo_names AS SELECT uuid FROM foo WHERE name = 'o''brien' AND   kind = 'it''s'"
+  if sql != expected {
+    t.Errorf("expected quoted literals:\n%s\ngot:\n%s", expected, sql)
+  }
+}
+
+func TestRefreshMaterializedView(t *testing.T) {
+  if sql := RefreshMaterializedView("foo_names").Query(); sql != "REFRESH MATERIALIZED VIEW foo_names" {
+    t.Errorf("unexpected sql: %s", sql)
+  }
+  if sql := RefreshMaterializedView("foo_names").Concurrently().Query(); sql != "REFRESH MATERIALIZED VIEW CONCURRENTLY foo_names" {
+    t.Errorf("unexpected sql: %s", sql)
+  }
+}